@@ -1,6 +1,10 @@
 package salesforce
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,6 +13,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -23,6 +28,7 @@ const (
 	AuthFlowClientCredentials
 	AuthFlowAccessToken
 	AuthFlowJWT
+	AuthFlowRefreshToken
 )
 
 func (a AuthFlowType) String() string {
@@ -35,21 +41,93 @@ func (a AuthFlowType) String() string {
 		return "Access Token"
 	case AuthFlowJWT:
 		return "JWT"
+	case AuthFlowRefreshToken:
+		return "Refresh Token"
 	default:
 		return "Unknown"
 	}
 }
 
 type authentication struct {
-	AccessToken string `json:"access_token"`
-	InstanceUrl string `json:"instance_url"`
-	Id          string `json:"id"`
-	TokenType   string `json:"token_type"`
-	Scope       string `json:"scope"`
-	IssuedAt    string `json:"issued_at"`
-	Signature   string `json:"signature"`
-	grantType   string
-	creds       Creds
+	AccessToken  string `json:"access_token"`
+	InstanceUrl  string `json:"instance_url"`
+	Id           string `json:"id"`
+	TokenType    string `json:"token_type"`
+	Scope        string `json:"scope"`
+	IssuedAt     string `json:"issued_at"`
+	Signature    string `json:"signature"`
+	RefreshToken string `json:"refresh_token"`
+	grantType    string
+	creds        Creds
+	session      *session
+}
+
+// session guards the token of an authentication that is shared by concurrent requests.
+// Init always sets it; an authentication without a session is only safe for use by one goroutine.
+type session struct {
+	mu         sync.RWMutex
+	refreshing *refreshCall // in-flight refresh, nil when there is none
+}
+
+type refreshCall struct {
+	done chan struct{} // closed once err is set
+	err  error
+}
+
+func (auth *authentication) accessToken() string {
+	if auth.session != nil {
+		auth.session.mu.RLock()
+		defer auth.session.mu.RUnlock()
+	}
+	return auth.AccessToken
+}
+
+func (auth *authentication) instanceUrl() string {
+	if auth.session != nil {
+		auth.session.mu.RLock()
+		defer auth.session.mu.RUnlock()
+	}
+	return auth.InstanceUrl
+}
+
+// endpoint returns the instance url and access token from one snapshot, so a request never pairs
+// the url of one session with the token of another.
+func (auth *authentication) endpoint() (string, string) {
+	if auth.session != nil {
+		auth.session.mu.RLock()
+		defer auth.session.mu.RUnlock()
+	}
+	return auth.InstanceUrl, auth.AccessToken
+}
+
+// token returns the persistable part of the session.
+func (auth *authentication) token() Token {
+	if auth.session != nil {
+		auth.session.mu.RLock()
+		defer auth.session.mu.RUnlock()
+	}
+	return Token{
+		AccessToken:  auth.AccessToken,
+		InstanceUrl:  auth.InstanceUrl,
+		IssuedAt:     auth.IssuedAt,
+		RefreshToken: auth.creds.RefreshToken,
+	}
+}
+
+// setToken replaces the session token, keeping the current refresh token if the new one has none.
+func (auth *authentication) setToken(token Token) {
+	if auth.session != nil {
+		auth.session.mu.Lock()
+		defer auth.session.mu.Unlock()
+	}
+	auth.AccessToken = token.AccessToken
+	auth.IssuedAt = token.IssuedAt
+	if token.InstanceUrl != "" {
+		auth.InstanceUrl = token.InstanceUrl
+	}
+	if token.RefreshToken != "" {
+		auth.creds.RefreshToken = token.RefreshToken
+	}
 }
 
 type Creds struct {
@@ -61,6 +139,7 @@ type Creds struct {
 	ConsumerSecret string
 	ConsumerRSAPem string
 	AccessToken    string
+	RefreshToken   string
 }
 
 const JwtExpirationTime = 5 * time.Minute
@@ -70,20 +149,61 @@ const (
 	grantTypeClientCredentials = "client_credentials"
 	grantTypeAccessToken       = "access_token"
 	grantTypeJWT               = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	grantTypeRefreshToken      = "refresh_token"
+	grantTypeAuthorizationCode = "authorization_code"
 )
 
+// selectAuthFlow determines the authentication flow from the credentials that are set.
+func selectAuthFlow(creds Creds) AuthFlowType {
+	switch {
+	case creds.Domain != "" && creds.ConsumerKey != "" && creds.ConsumerSecret != "" &&
+		creds.Username != "" && creds.Password != "" && creds.SecurityToken != "":
+		return AuthFlowUsernamePassword
+	case creds.Domain != "" && creds.ConsumerKey != "" && creds.RefreshToken != "" &&
+		creds.AccessToken == "":
+		return AuthFlowRefreshToken
+	case creds.Domain != "" && creds.ConsumerKey != "" && creds.ConsumerSecret != "" &&
+		creds.RefreshToken == "":
+		return AuthFlowClientCredentials
+	case creds.AccessToken != "":
+		return AuthFlowAccessToken
+	case creds.Domain != "" && creds.Username != "" &&
+		creds.ConsumerKey != "" && creds.ConsumerRSAPem != "":
+		return AuthFlowJWT
+	default:
+		return AuthFlowUnknown
+	}
+}
+
+func (a AuthFlowType) grantType() string {
+	switch a {
+	case AuthFlowUsernamePassword:
+		return grantTypeUsernamePassword
+	case AuthFlowClientCredentials:
+		return grantTypeClientCredentials
+	case AuthFlowAccessToken:
+		return grantTypeAccessToken
+	case AuthFlowJWT:
+		return grantTypeJWT
+	case AuthFlowRefreshToken:
+		return grantTypeRefreshToken
+	default:
+		return ""
+	}
+}
+
 func validateAuth(sf Salesforce) error {
-	if sf.auth == nil || sf.auth.AccessToken == "" {
+	if sf.auth == nil || sf.auth.accessToken() == "" {
 		return errors.New("not authenticated: please use salesforce.Init()")
 	}
 	return nil
 }
 
-func (conf *configuration) validateAuthentication(auth authentication) error {
-	if err := validateAuth(Salesforce{auth: &auth}); err != nil {
+func (conf *configuration) validateAuthentication(ctx context.Context, auth *authentication) error {
+	if err := validateAuth(Salesforce{auth: auth}); err != nil {
 		return err
 	}
-	_, err := doRequest(&auth, conf, requestPayload{
+	_, err := doRequest(ctx, auth, conf, requestPayload{
 		method:  http.MethodGet,
 		uri:     "/limits",
 		content: jsonType,
@@ -95,20 +215,35 @@ func (conf *configuration) validateAuthentication(auth authentication) error {
 	return nil
 }
 
-func refreshSession(auth *authentication) error {
+func refreshSession(ctx context.Context, auth *authentication, config *configuration) error {
 	var refreshedAuth *authentication
 	var err error
 
+	if config.tokenStore != nil {
+		// another process sharing the store may have refreshed the session already
+		stored, err := config.tokenStore.Load(ctx)
+		if err != nil {
+			return fmt.Errorf("loading token from store: %w", err)
+		}
+		if stored != nil && stored.AccessToken != "" && stored.AccessToken != auth.accessToken() {
+			auth.setToken(*stored)
+			return nil
+		}
+	}
+
+	instanceUrl := auth.instanceUrl()
 	switch grantType := auth.grantType; grantType {
 	case grantTypeClientCredentials:
-		refreshedAuth, err = clientCredentialsFlow(
-			auth.InstanceUrl,
+		refreshedAuth, err = config.clientCredentialsFlow(
+			ctx,
+			instanceUrl,
 			auth.creds.ConsumerKey,
 			auth.creds.ConsumerSecret,
 		)
 	case grantTypeUsernamePassword:
-		refreshedAuth, err = usernamePasswordFlow(
-			auth.InstanceUrl,
+		refreshedAuth, err = config.usernamePasswordFlow(
+			ctx,
+			instanceUrl,
 			auth.creds.Username,
 			auth.creds.Password,
 			auth.creds.SecurityToken,
@@ -116,13 +251,25 @@ func refreshSession(auth *authentication) error {
 			auth.creds.ConsumerSecret,
 		)
 	case grantTypeJWT:
-		refreshedAuth, err = jwtFlow(
-			auth.InstanceUrl,
+		refreshedAuth, err = config.jwtFlow(
+			ctx,
+			instanceUrl,
 			auth.creds.Username,
 			auth.creds.ConsumerKey,
 			auth.creds.ConsumerRSAPem,
 			JwtExpirationTime,
 		)
+	case grantTypeRefreshToken, grantTypeAccessToken:
+		if auth.creds.RefreshToken == "" {
+			return errors.New("invalid session, unable to refresh session without a refresh token")
+		}
+		refreshedAuth, err = config.refreshTokenFlow(
+			ctx,
+			instanceUrl,
+			auth.creds.RefreshToken,
+			auth.creds.ConsumerKey,
+			auth.creds.ConsumerSecret,
+		)
 	default:
 		return errors.New("invalid session, unable to refresh session")
 	}
@@ -135,21 +282,86 @@ func refreshSession(auth *authentication) error {
 		return errors.New("missing refresh auth")
 	}
 
+	if auth.session != nil {
+		auth.session.mu.Lock()
+	}
 	auth.AccessToken = refreshedAuth.AccessToken
 	auth.IssuedAt = refreshedAuth.IssuedAt
 	auth.Signature = refreshedAuth.Signature
 	auth.Id = refreshedAuth.Id
+	if refreshedAuth.RefreshToken != "" { // the connected app rotates refresh tokens
+		auth.creds.RefreshToken = refreshedAuth.RefreshToken
+	}
+	if auth.session != nil {
+		auth.session.mu.Unlock()
+	}
+
+	if config.tokenStore != nil {
+		if err := config.tokenStore.Save(ctx, auth.token()); err != nil {
+			return fmt.Errorf("saving token to store: %w", err)
+		}
+	}
 
 	return nil
 }
 
-func doAuth(url string, body *strings.Reader) (*authentication, error) {
-	resp, err := http.Post(url, "application/x-www-form-urlencoded", body)
+// refreshSessionOnce refreshes the session unless the token that was rejected has already been replaced.
+// Concurrent callers share a single in-flight refresh, so only one request hits the token endpoint.
+func refreshSessionOnce(
+	ctx context.Context,
+	auth *authentication,
+	config *configuration,
+	rejectedToken string,
+) error {
+	s := auth.session
+	if s == nil {
+		return refreshSession(ctx, auth, config)
+	}
+
+	s.mu.Lock()
+	if rejectedToken != "" && auth.AccessToken != rejectedToken {
+		s.mu.Unlock()
+		return nil
+	}
+	call := s.refreshing
+	if call != nil {
+		s.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	call = &refreshCall{done: make(chan struct{})}
+	s.refreshing = call
+	s.mu.Unlock()
+
+	call.err = refreshSession(ctx, auth, config)
+
+	s.mu.Lock()
+	s.refreshing = nil
+	s.mu.Unlock()
+	close(call.done)
+	return call.err
+}
+
+func (conf *configuration) doAuth(
+	ctx context.Context,
+	url string,
+	body *strings.Reader,
+) (*authentication, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := conf.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New(string(resp.Status) + ":" + " failed authentication")
+		return nil, newAuthError(resp, req.URL.Path)
 	}
 
 	respBody, err := io.ReadAll(resp.Body)
@@ -169,7 +381,33 @@ func doAuth(url string, body *strings.Reader) (*authentication, error) {
 	return auth, err
 }
 
-func usernamePasswordFlow(
+// newAuthError converts an OAuth error response, e.g. {"error":"invalid_grant","error_description":"..."}
+func newAuthError(resp *http.Response, path string) error {
+	respBody, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     http.MethodPost,
+		URI:        path,
+		Body:       respBody,
+	}
+	oauthErr := struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}{}
+	if json.Unmarshal(respBody, &oauthErr) == nil && oauthErr.Error != "" {
+		apiErr.Errors = []SalesforceErrorMessage{{
+			ErrorCode: oauthErr.Error,
+			Message:   oauthErr.ErrorDescription,
+		}}
+	} else {
+		apiErr.Errors = []SalesforceErrorMessage{{Message: resp.Status + ": failed authentication"}}
+	}
+	return apiErr
+}
+
+func (conf *configuration) usernamePasswordFlow(
+	ctx context.Context,
 	domain string,
 	username string,
 	password string,
@@ -186,7 +424,7 @@ func usernamePasswordFlow(
 	}
 	endpoint := "/services/oauth2/token"
 	body := strings.NewReader(payload.Encode())
-	auth, err := doAuth(domain+endpoint, body)
+	auth, err := conf.doAuth(ctx, domain+endpoint, body)
 	if err != nil {
 		return nil, err
 	}
@@ -194,7 +432,8 @@ func usernamePasswordFlow(
 	return auth, nil
 }
 
-func clientCredentialsFlow(
+func (conf *configuration) clientCredentialsFlow(
+	ctx context.Context,
 	domain string,
 	consumerKey string,
 	consumerSecret string,
@@ -206,7 +445,7 @@ func clientCredentialsFlow(
 	}
 	endpoint := "/services/oauth2/token"
 	body := strings.NewReader(payload.Encode())
-	auth, err := doAuth(domain+endpoint, body)
+	auth, err := conf.doAuth(ctx, domain+endpoint, body)
 	if err != nil {
 		return nil, err
 	}
@@ -214,21 +453,57 @@ func clientCredentialsFlow(
 	return auth, nil
 }
 
-func (conf *configuration) getAccessTokenAuthentication(
+func (conf *configuration) refreshTokenFlow(
+	ctx context.Context,
 	domain string,
-	accessToken string,
+	refreshToken string,
+	consumerKey string,
+	consumerSecret string,
+) (*authentication, error) {
+	payload := url.Values{
+		"grant_type":    {grantTypeRefreshToken},
+		"client_id":     {consumerKey},
+		"refresh_token": {refreshToken},
+	}
+	if consumerSecret != "" {
+		payload.Set("client_secret", consumerSecret)
+	}
+	endpoint := "/services/oauth2/token"
+	body := strings.NewReader(payload.Encode())
+	auth, err := conf.doAuth(ctx, domain+endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	auth.grantType = grantTypeRefreshToken
+	return auth, nil
+}
+
+// getAccessTokenAuthentication uses the access token of creds. An expired token is refreshed while it is
+// validated when creds also has a refresh token, e.g. the creds returned by WebServerFlow.Exchange.
+func (conf *configuration) getAccessTokenAuthentication(
+	ctx context.Context,
+	creds Creds,
 ) (*authentication, error) {
-	auth := &authentication{InstanceUrl: domain, AccessToken: accessToken}
+	auth := &authentication{
+		InstanceUrl: creds.Domain,
+		AccessToken: creds.AccessToken,
+		grantType:   grantTypeAccessToken,
+		creds:       creds,
+		session:     &session{},
+	}
 	if conf.shouldValidateAuthentication {
-		if err := conf.validateAuthentication(*auth); err != nil {
+		// the token passed to Init replaces the stored one, which must not be picked up when it is refreshed
+		validateConf := *conf
+		validateConf.tokenStore = nil
+		if err := validateConf.validateAuthentication(ctx, auth); err != nil {
 			return nil, err
 		}
 	}
-	auth.grantType = grantTypeAccessToken
 	return auth, nil
 }
 
-func jwtFlow(
+func (conf *configuration) jwtFlow(
+	ctx context.Context,
 	domain string,
 	username string,
 	consumerKey string,
@@ -263,10 +538,110 @@ func jwtFlow(
 	}
 	endpoint := "/services/oauth2/token"
 	body := strings.NewReader(payload.Encode())
-	auth, err := doAuth(domain+endpoint, body)
+	auth, err := conf.doAuth(ctx, domain+endpoint, body)
 	if err != nil {
 		return nil, err
 	}
 	auth.grantType = grantTypeJWT
 	return auth, nil
 }
+
+// PKCE holds a Proof Key for Code Exchange pair for the web server flow.
+// Send the Challenge with the authorization request and the Verifier with the code exchange.
+type PKCE struct {
+	Verifier  string
+	Challenge string
+	Method    string
+}
+
+// NewPKCE generates a random code verifier and its S256 code challenge
+func NewPKCE() (PKCE, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return PKCE{}, err
+	}
+	verifier := base64.RawURLEncoding.EncodeToString(random)
+	hash := sha256.Sum256([]byte(verifier))
+	return PKCE{
+		Verifier:  verifier,
+		Challenge: base64.RawURLEncoding.EncodeToString(hash[:]),
+		Method:    "S256",
+	}, nil
+}
+
+// WebServerFlow describes a connected app using the OAuth 2.0 web server (authorization code) flow,
+// which lets an application act on behalf of the users who approve it.
+type WebServerFlow struct {
+	Domain         string   // login or My Domain URL, e.g. https://login.salesforce.com
+	ConsumerKey    string   // connected app consumer key
+	ConsumerSecret string   // connected app consumer secret, may be empty if the app does not require it
+	RedirectURI    string   // callback URL registered on the connected app
+	Scopes         []string // requested scopes, e.g. "api" and "refresh_token"; the app's defaults if empty
+}
+
+// AuthorizeURL returns the URL to send the user to for approving access.
+// state is returned unchanged to the redirect URI; pkce is optional.
+func (w WebServerFlow) AuthorizeURL(state string, pkce *PKCE) string {
+	params := url.Values{
+		"response_type": {"code"},
+		"client_id":     {w.ConsumerKey},
+		"redirect_uri":  {w.RedirectURI},
+	}
+	if state != "" {
+		params.Set("state", state)
+	}
+	if len(w.Scopes) > 0 {
+		params.Set("scope", strings.Join(w.Scopes, " "))
+	}
+	if pkce != nil {
+		params.Set("code_challenge", pkce.Challenge)
+		params.Set("code_challenge_method", pkce.Method)
+	}
+	return w.Domain + "/services/oauth2/authorize?" + params.Encode()
+}
+
+// Exchange trades the authorization code received on the redirect URI for tokens.
+// The returned Creds can be passed to Init, and contain a RefreshToken when the refresh_token scope was granted,
+// which keeps the session refreshable and can be stored to create new sessions later without user interaction.
+// HTTP options such as WithRoundTripper and WithHTTPTimeout apply to the token request.
+func (w WebServerFlow) Exchange(
+	ctx context.Context,
+	code string,
+	pkce *PKCE,
+	options ...Option,
+) (Creds, error) {
+	config := &configuration{}
+	config.setDefaults()
+	for _, option := range options {
+		if err := option(config); err != nil {
+			return Creds{}, fmt.Errorf("configuration error: %w", err)
+		}
+	}
+	config.configureHttpClient()
+
+	payload := url.Values{
+		"grant_type":   {grantTypeAuthorizationCode},
+		"code":         {code},
+		"client_id":    {w.ConsumerKey},
+		"redirect_uri": {w.RedirectURI},
+	}
+	if w.ConsumerSecret != "" {
+		payload.Set("client_secret", w.ConsumerSecret)
+	}
+	if pkce != nil {
+		payload.Set("code_verifier", pkce.Verifier)
+	}
+	endpoint := "/services/oauth2/token"
+	body := strings.NewReader(payload.Encode())
+	auth, err := config.doAuth(ctx, w.Domain+endpoint, body)
+	if err != nil {
+		return Creds{}, err
+	}
+	return Creds{
+		Domain:         auth.InstanceUrl,
+		ConsumerKey:    w.ConsumerKey,
+		ConsumerSecret: w.ConsumerSecret,
+		AccessToken:    auth.AccessToken,
+		RefreshToken:   auth.RefreshToken,
+	}, nil
+}
@@ -1,9 +1,19 @@
 package salesforce
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -101,7 +111,8 @@ func Test_usernamePasswordFlow(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := usernamePasswordFlow(
+			got, err := getDefaultConfig(t).usernamePasswordFlow(
+				context.Background(),
 				tt.args.domain,
 				tt.args.username,
 				tt.args.password,
@@ -169,7 +180,8 @@ func Test_clientCredentialsFlow(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := clientCredentialsFlow(
+			got, err := getDefaultConfig(t).clientCredentialsFlow(
+				context.Background(),
 				tt.args.domain,
 				tt.args.consumerKey,
 				tt.args.consumerSecret,
@@ -237,7 +249,10 @@ func Test_setAccessToken(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			config := getDefaultConfig(t)
-			got, err := config.getAccessTokenAuthentication(tt.args.domain, tt.args.accessToken)
+			got, err := config.getAccessTokenAuthentication(
+				context.Background(),
+				Creds{Domain: tt.args.domain, AccessToken: tt.args.accessToken},
+			)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("setAccessToken() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -304,6 +319,23 @@ func Test_refreshSession(t *testing.T) {
 	defer serverNoRefresh.Close()
 	sfAuthNoRefresh.grantType = grantTypeClientCredentials
 
+	serverRefreshToken, sfAuthRefreshToken := setupTestServer(
+		authentication{AccessToken: "1234", RefreshToken: "rotated"},
+		http.StatusOK,
+	)
+	defer serverRefreshToken.Close()
+	sfAuthRefreshToken.grantType = grantTypeRefreshToken
+	sfAuthRefreshToken.creds = Creds{ConsumerKey: "key", RefreshToken: "refresh"}
+
+	serverAccessToken, sfAuthAccessToken := setupTestServer(refreshedAuth, http.StatusOK)
+	defer serverAccessToken.Close()
+	sfAuthAccessToken.grantType = grantTypeAccessToken
+	sfAuthAccessToken.creds = Creds{ConsumerKey: "key", RefreshToken: "refresh"}
+
+	serverAccessTokenOnly, sfAuthAccessTokenOnly := setupTestServer(refreshedAuth, http.StatusOK)
+	defer serverAccessTokenOnly.Close()
+	sfAuthAccessTokenOnly.grantType = grantTypeAccessToken
+
 	type args struct {
 		auth *authentication
 	}
@@ -342,16 +374,347 @@ func Test_refreshSession(t *testing.T) {
 			args:    args{auth: &sfAuthNoRefresh},
 			wantErr: true,
 		},
+		{
+			name:    "refresh_refresh_token",
+			args:    args{auth: &sfAuthRefreshToken},
+			wantErr: false,
+		},
+		{
+			name:    "refresh_access_token_with_refresh_token",
+			args:    args{auth: &sfAuthAccessToken},
+			wantErr: false,
+		},
+		{
+			name:    "error_access_token_without_refresh_token",
+			args:    args{auth: &sfAuthAccessTokenOnly},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := refreshSession(tt.args.auth); (err != nil) != tt.wantErr {
+			if err := refreshSession(context.Background(), tt.args.auth, getDefaultConfig(t)); (err != nil) != tt.wantErr {
 				t.Errorf("refreshSession() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func Test_refreshSession_rotatesRefreshToken(t *testing.T) {
+	server, sfAuth := setupTestServer(
+		authentication{AccessToken: "new", RefreshToken: "rotated"},
+		http.StatusOK,
+	)
+	defer server.Close()
+	sfAuth.grantType = grantTypeRefreshToken
+	sfAuth.creds = Creds{ConsumerKey: "key", RefreshToken: "original"}
+
+	if err := refreshSession(context.Background(), &sfAuth, getDefaultConfig(t)); err != nil {
+		t.Fatalf("refreshSession() error = %v", err)
+	}
+	if sfAuth.AccessToken != "new" || sfAuth.creds.RefreshToken != "rotated" {
+		t.Errorf(
+			"refreshSession() access token = %v, refresh token = %v",
+			sfAuth.AccessToken,
+			sfAuth.creds.RefreshToken,
+		)
+	}
+}
+
+func Test_refreshTokenFlow(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			panic(err)
+		}
+		form = r.PostForm
+		body, _ := json.Marshal(authentication{AccessToken: "1234", InstanceUrl: "example.com"})
+		if _, err := w.Write(body); err != nil {
+			panic(err)
+		}
+	}))
+	defer server.Close()
+
+	badServer, _ := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	tests := []struct {
+		name           string
+		domain         string
+		consumerSecret string
+		wantForm       url.Values
+		wantErr        bool
+	}{
+		{
+			name:           "public_client",
+			domain:         server.URL,
+			consumerSecret: "",
+			wantForm: url.Values{
+				"grant_type":    {grantTypeRefreshToken},
+				"client_id":     {"key"},
+				"refresh_token": {"refresh"},
+			},
+			wantErr: false,
+		},
+		{
+			name:           "confidential_client",
+			domain:         server.URL,
+			consumerSecret: "secret",
+			wantForm: url.Values{
+				"grant_type":    {grantTypeRefreshToken},
+				"client_id":     {"key"},
+				"client_secret": {"secret"},
+				"refresh_token": {"refresh"},
+			},
+			wantErr: false,
+		},
+		{
+			name:           "authentication_fail",
+			domain:         badServer.URL,
+			consumerSecret: "",
+			wantForm:       nil,
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form = nil
+			got, err := getDefaultConfig(t).refreshTokenFlow(
+				context.Background(),
+				tt.domain,
+				"refresh",
+				"key",
+				tt.consumerSecret,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("refreshTokenFlow() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(form, tt.wantForm) {
+				t.Errorf("refreshTokenFlow() form = %v, want %v", form, tt.wantForm)
+			}
+			if !tt.wantErr && got.grantType != grantTypeRefreshToken {
+				t.Errorf("refreshTokenFlow() grantType = %v", got.grantType)
+			}
+		})
+	}
+}
+
+func TestNewPKCE(t *testing.T) {
+	pkce, err := NewPKCE()
+	if err != nil {
+		t.Fatalf("NewPKCE() error = %v", err)
+	}
+	if len(pkce.Verifier) < 43 || len(pkce.Verifier) > 128 {
+		t.Errorf("NewPKCE() verifier length = %v, want 43-128", len(pkce.Verifier))
+	}
+	hash := sha256.Sum256([]byte(pkce.Verifier))
+	if want := base64.RawURLEncoding.EncodeToString(hash[:]); pkce.Challenge != want {
+		t.Errorf("NewPKCE() challenge = %v, want %v", pkce.Challenge, want)
+	}
+	if pkce.Method != "S256" {
+		t.Errorf("NewPKCE() method = %v, want S256", pkce.Method)
+	}
+}
+
+func TestWebServerFlow_AuthorizeURL(t *testing.T) {
+	flow := WebServerFlow{
+		Domain:      "https://login.salesforce.com",
+		ConsumerKey: "key",
+		RedirectURI: "https://example.com/callback",
+		Scopes:      []string{"api", "refresh_token"},
+	}
+	pkce := &PKCE{Verifier: "verifier", Challenge: "challenge", Method: "S256"}
+
+	got, err := url.Parse(flow.AuthorizeURL("xyz", pkce))
+	if err != nil {
+		t.Fatalf("AuthorizeURL() returned invalid url: %v", err)
+	}
+	if got.Path != "/services/oauth2/authorize" {
+		t.Errorf("AuthorizeURL() path = %v", got.Path)
+	}
+	want := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {"key"},
+		"redirect_uri":          {"https://example.com/callback"},
+		"state":                 {"xyz"},
+		"scope":                 {"api refresh_token"},
+		"code_challenge":        {"challenge"},
+		"code_challenge_method": {"S256"},
+	}
+	if !reflect.DeepEqual(got.Query(), want) {
+		t.Errorf("AuthorizeURL() query = %v, want %v", got.Query(), want)
+	}
+}
+
+func TestWebServerFlow_Exchange(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			panic(err)
+		}
+		form = r.PostForm
+		body, _ := json.Marshal(authentication{
+			AccessToken:  "1234",
+			RefreshToken: "refresh",
+			InstanceUrl:  "https://myorg.my.salesforce.com",
+		})
+		if _, err := w.Write(body); err != nil {
+			panic(err)
+		}
+	}))
+	defer server.Close()
+
+	flow := WebServerFlow{
+		Domain:      server.URL,
+		ConsumerKey: "key",
+		RedirectURI: "https://example.com/callback",
+	}
+	got, err := flow.Exchange(context.Background(), "code123", &PKCE{Verifier: "verifier"})
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	wantForm := url.Values{
+		"grant_type":    {grantTypeAuthorizationCode},
+		"code":          {"code123"},
+		"client_id":     {"key"},
+		"redirect_uri":  {"https://example.com/callback"},
+		"code_verifier": {"verifier"},
+	}
+	if !reflect.DeepEqual(form, wantForm) {
+		t.Errorf("Exchange() form = %v, want %v", form, wantForm)
+	}
+	want := Creds{
+		Domain:       "https://myorg.my.salesforce.com",
+		ConsumerKey:  "key",
+		AccessToken:  "1234",
+		RefreshToken: "refresh",
+	}
+	if got != want {
+		t.Errorf("Exchange() = %v, want %v", got, want)
+	}
+}
+
+func Test_refreshSessionOnce_concurrentRequests(t *testing.T) {
+	var tokenRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.RequestURI, "/oauth2/token") {
+			tokenRequests.Add(1)
+			time.Sleep(10 * time.Millisecond) // give the other requests time to pile up
+			body, _ := json.Marshal(authentication{AccessToken: "refreshed"})
+			if _, err := w.Write(body); err != nil {
+				panic(err)
+			}
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer refreshed" {
+			w.WriteHeader(http.StatusUnauthorized)
+			body, _ := json.Marshal([]SalesforceErrorMessage{{ErrorCode: invalidSessionIdError}})
+			if _, err := w.Write(body); err != nil {
+				panic(err)
+			}
+			return
+		}
+		body, _ := json.Marshal(queryResponse{Done: true})
+		if _, err := w.Write(body); err != nil {
+			panic(err)
+		}
+	}))
+	defer server.Close()
+
+	sf := buildSalesforceStruct(&authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "expired",
+		grantType:   grantTypeClientCredentials,
+		creds:       Creds{ConsumerKey: "key", ConsumerSecret: "secret"},
+		session:     &session{},
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			records := []map[string]any{}
+			errs <- sf.Query("SELECT Id FROM Account", &records)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Query() error = %v", err)
+		}
+	}
+	if got := tokenRequests.Load(); got != 1 {
+		t.Errorf("token endpoint called %v times, want 1", got)
+	}
+	if got := sf.GetAccessToken(); got != "refreshed" {
+		t.Errorf("GetAccessToken() = %v, want %v", got, "refreshed")
+	}
+}
+
+func Test_refreshSessionOnce_storedTokenDuringRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer stored" {
+			w.WriteHeader(http.StatusUnauthorized)
+			body, _ := json.Marshal([]SalesforceErrorMessage{{ErrorCode: invalidSessionIdError}})
+			if _, err := w.Write(body); err != nil {
+				panic(err)
+			}
+			return
+		}
+		body, _ := json.Marshal(queryResponse{Done: true})
+		if _, err := w.Write(body); err != nil {
+			panic(err)
+		}
+	}))
+	defer server.Close()
+
+	sf := buildSalesforceStruct(&authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "expired",
+		grantType:   grantTypeClientCredentials,
+		creds:       Creds{ConsumerKey: "key", ConsumerSecret: "secret"},
+		session:     &session{},
+	})
+	store := NewMemoryTokenStore()
+	if err := store.Save(context.Background(), Token{AccessToken: "stored", InstanceUrl: server.URL}); err != nil {
+		t.Fatal(err.Error())
+	}
+	sf.config.tokenStore = store
+
+	// run with -race: adopting the stored token rewrites the instance url while requests read it
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			records := []map[string]any{}
+			errs <- sf.Query("SELECT Id FROM Account", &records)
+		}()
+		go func() {
+			defer wg.Done()
+			if got := sf.GetInstanceUrl(); got != server.URL {
+				errs <- fmt.Errorf("GetInstanceUrl() = %v, want %v", got, server.URL)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Query() error = %v", err)
+		}
+	}
+	if got := sf.GetAccessToken(); got != "stored" {
+		t.Errorf("GetAccessToken() = %v, want %v", got, "stored")
+	}
+}
+
 func Test_jwtFlow(t *testing.T) {
 	auth := authentication{
 		AccessToken: "1234",
@@ -406,7 +769,8 @@ func Test_jwtFlow(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := jwtFlow(
+			got, err := getDefaultConfig(t).jwtFlow(
+				context.Background(),
 				tt.args.domain,
 				tt.args.username,
 				tt.args.consumerKey,
@@ -460,6 +824,11 @@ func TestAuthFlowType_String(t *testing.T) {
 			want:     "JWT",
 			receiver: 4,
 		},
+		{
+			name:     "refresh_token",
+			want:     "Refresh Token",
+			receiver: 5,
+		},
 		{
 			name:     "unknown",
 			want:     "Unknown",
@@ -8,23 +8,85 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/afero"
 )
 
 type bulkJobCreationRequest struct {
-	Object              string `json:"object"`
-	Operation           string `json:"operation"`
-	ExternalIdFieldName string `json:"externalIdFieldName"`
-	AssignmentRuleId    string `json:"assignmentRuleId,omitempty"`
+	Object              string          `json:"object"`
+	Operation           string          `json:"operation"`
+	ExternalIdFieldName string          `json:"externalIdFieldName"`
+	AssignmentRuleId    string          `json:"assignmentRuleId,omitempty"`
+	ColumnDelimiter     ColumnDelimiter `json:"columnDelimiter,omitempty"`
+	LineEnding          LineEnding      `json:"lineEnding,omitempty"`
 }
 
 type bulkQueryJobCreationRequest struct {
-	Operation string `json:"operation"`
-	Query     string `json:"query"`
+	Operation       string          `json:"operation"`
+	Query           string          `json:"query"`
+	ColumnDelimiter ColumnDelimiter `json:"columnDelimiter,omitempty"`
+	LineEnding      LineEnding      `json:"lineEnding,omitempty"`
+}
+
+// ColumnDelimiter separates the columns of the CSV data of a bulk job
+type ColumnDelimiter string
+
+const (
+	ColumnDelimiterComma     ColumnDelimiter = "COMMA"
+	ColumnDelimiterTab       ColumnDelimiter = "TAB"
+	ColumnDelimiterPipe      ColumnDelimiter = "PIPE"
+	ColumnDelimiterSemicolon ColumnDelimiter = "SEMICOLON"
+	ColumnDelimiterCaret     ColumnDelimiter = "CARET"
+	ColumnDelimiterBackquote ColumnDelimiter = "BACKQUOTE"
+)
+
+var columnDelimiters = map[ColumnDelimiter]rune{
+	ColumnDelimiterComma:     ',',
+	ColumnDelimiterTab:       '\t',
+	ColumnDelimiterPipe:      '|',
+	ColumnDelimiterSemicolon: ';',
+	ColumnDelimiterCaret:     '^',
+	ColumnDelimiterBackquote: '`',
+}
+
+// LineEnding ends the rows of the CSV data of a bulk job
+type LineEnding string
+
+const (
+	LineEndingLF   LineEnding = "LF"
+	LineEndingCRLF LineEnding = "CRLF"
+)
+
+// csvFormat is the format of the CSV data of a bulk job, which is also the format of its results.
+// The zero value uses commas and LF line endings.
+type csvFormat struct {
+	ColumnDelimiter ColumnDelimiter
+	LineEnding      LineEnding
+}
+
+func (o csvFormat) csvReader(r io.Reader) *csv.Reader {
+	reader := csv.NewReader(r)
+	if comma, ok := columnDelimiters[o.ColumnDelimiter]; ok {
+		reader.Comma = comma
+	}
+	return reader
+}
+
+func (o csvFormat) csvWriter(w io.Writer) *csv.Writer {
+	writer := csv.NewWriter(w)
+	if comma, ok := columnDelimiters[o.ColumnDelimiter]; ok {
+		writer.Comma = comma
+	}
+	writer.UseCRLF = o.LineEnding == LineEndingCRLF
+	return writer
 }
 
 type bulkJob struct {
@@ -33,18 +95,27 @@ type bulkJob struct {
 }
 
 type BulkJobResults struct {
-	Id                  string `json:"id"`
-	State               string `json:"state"`
-	NumberRecordsFailed int    `json:"numberRecordsFailed"`
-	ErrorMessage        string `json:"errorMessage"`
-	SuccessfulRecords   []map[string]any
-	FailedRecords       []map[string]any
+	Id                     string          `json:"id"`
+	State                  string          `json:"state"`
+	NumberRecordsFailed    int             `json:"numberRecordsFailed"`
+	NumberRecordsProcessed int             `json:"numberRecordsProcessed"`
+	ErrorMessage           string          `json:"errorMessage"`
+	ColumnDelimiter        ColumnDelimiter `json:"columnDelimiter"` // delimiter of the job's data and results
+	SuccessfulRecords      []map[string]any
+	FailedRecords          []map[string]any
+	UnprocessedRecords     []map[string]any
+	Successful             []BulkRecordResult // SuccessfulRecords as typed rows
+	Failed                 []BulkRecordResult // FailedRecords as typed rows
+	Unprocessed            []BulkRecordResult // UnprocessedRecords as typed rows, e.g. after the job was aborted
 }
 
-type bulkJobQueryResults struct {
-	NumberOfRecords int        `json:"Sforce-Numberofrecords"`
-	Locator         string     `json:"Sforce-Locator"`
-	Data            [][]string `json:"data"`
+// BulkRecordResult is one row of the results of a bulk ingest job
+type BulkRecordResult struct {
+	Id      string            // Id of the record, empty when it failed to be created
+	Created bool              // whether the record was created rather than updated
+	Error   string            // error of a failed record, e.g. "REQUIRED_FIELD_MISSING:Required fields are missing"
+	Fields  map[string]string // the uploaded values of the record
+	Index   int               // position of the record in the input set by MatchBulkResults, otherwise -1
 }
 
 const (
@@ -53,22 +124,30 @@ const (
 	jobStateJobComplete    = "JobComplete"
 	jobStateFailed         = "Failed"
 	jobStateOpen           = "Open"
+	lastPageLocator        = "null" // Sforce-Locator of the last page of query results
 	insertOperation        = "insert"
 	updateOperation        = "update"
 	upsertOperation        = "upsert"
 	deleteOperation        = "delete"
+	hardDeleteOperation    = "hardDelete"
 	ingestJobType          = "ingest"
 	queryJobType           = "query"
+	queryAllOperation      = "queryAll"
 	failedResults          = "failedResults"
 	successfulResults      = "successfulResults"
+	unprocessedResults     = "unprocessedrecords"
 )
 
 var appFs = afero.NewOsFs() // afero.Fs type is a wrapper around os functions, allowing us to mock it in tests
 
-func updateJobState(job bulkJob, state string, sf *Salesforce) error {
+// bulkUploadSizeMax is the most CSV data uploaded to one job. Salesforce limits job data to 150MB after
+// base64 encoding, which is 100MB before. A variable so tests can lower it.
+var bulkUploadSizeMax = 100 * 1024 * 1024
+
+func updateJobState(ctx context.Context, job bulkJob, state string, sf *Salesforce) error {
 	job.State = state
 	body, _ := json.Marshal(job)
-	_, err := doRequest(sf.auth, sf.config, requestPayload{
+	_, err := doRequest(ctx, sf.auth, sf.config, requestPayload{
 		method:   http.MethodPatch,
 		uri:      "/jobs/ingest/" + job.Id,
 		content:  jsonType,
@@ -82,8 +161,13 @@ func updateJobState(job bulkJob, state string, sf *Salesforce) error {
 	return nil
 }
 
-func createBulkJob(sf *Salesforce, jobType string, body []byte) (bulkJob, error) {
-	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+func createBulkJob(
+	ctx context.Context,
+	sf *Salesforce,
+	jobType string,
+	body []byte,
+) (bulkJob, error) {
+	resp, err := doRequest(ctx, sf.auth, sf.config, requestPayload{
 		method:   http.MethodPost,
 		uri:      "/jobs/" + jobType,
 		content:  jsonType,
@@ -108,21 +192,33 @@ func createBulkJob(sf *Salesforce, jobType string, body []byte) (bulkJob, error)
 	return *newJob, nil
 }
 
-func uploadJobData(sf *Salesforce, data string, bulkJob bulkJob) error {
-	_, uploadDataErr := doRequest(sf.auth, sf.config, requestPayload{
-		method:   http.MethodPut,
-		uri:      "/jobs/ingest/" + bulkJob.Id + "/batches",
-		content:  csvType,
-		body:     data,
-		compress: sf.config.compressionHeaders,
-	})
+func uploadJobData(ctx context.Context, sf *Salesforce, data string, bulkJob bulkJob) error {
+	return uploadJobPayload(ctx, sf, requestPayload{body: data}, bulkJob)
+}
+
+// uploadJobStream uploads job data without buffering it; the upload is not retried
+func uploadJobStream(ctx context.Context, sf *Salesforce, data io.Reader, bulkJob bulkJob) error {
+	return uploadJobPayload(ctx, sf, requestPayload{bodyReader: data}, bulkJob)
+}
+
+func uploadJobPayload(
+	ctx context.Context,
+	sf *Salesforce,
+	payload requestPayload,
+	bulkJob bulkJob,
+) error {
+	payload.method = http.MethodPut
+	payload.uri = "/jobs/ingest/" + bulkJob.Id + "/batches"
+	payload.content = csvType
+	payload.compress = sf.config.compressionHeaders
+	_, uploadDataErr := doRequest(ctx, sf.auth, sf.config, payload)
 	if uploadDataErr != nil {
-		if err := updateJobState(bulkJob, jobStateAborted, sf); err != nil {
+		if err := updateJobState(ctx, bulkJob, jobStateAborted, sf); err != nil {
 			return err
 		}
 		return uploadDataErr
 	}
-	stateErr := updateJobState(bulkJob, jobStateUploadComplete, sf)
+	stateErr := updateJobState(ctx, bulkJob, jobStateUploadComplete, sf)
 	if stateErr != nil {
 		return stateErr
 	}
@@ -130,8 +226,13 @@ func uploadJobData(sf *Salesforce, data string, bulkJob bulkJob) error {
 	return nil
 }
 
-func getJobResults(sf *Salesforce, jobType string, bulkJobId string) (BulkJobResults, error) {
-	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+func getJobResults(
+	ctx context.Context,
+	sf *Salesforce,
+	jobType string,
+	bulkJobId string,
+) (BulkJobResults, error) {
+	resp, err := doRequest(ctx, sf.auth, sf.config, requestPayload{
 		method:   http.MethodGet,
 		uri:      "/jobs/" + jobType + "/" + bulkJobId,
 		content:  jsonType,
@@ -155,26 +256,228 @@ func getJobResults(sf *Salesforce, jobType string, bulkJobId string) (BulkJobRes
 	return *bulkJobResults, nil
 }
 
-func getJobRecordResults(sf *Salesforce, bulkJobResults BulkJobResults) (BulkJobResults, error) {
-	successfulRecords, err := getBulkJobRecords(sf, bulkJobResults.Id, successfulResults)
+// BulkJobType is the kind of Bulk API v2 job, either BulkIngestJob or BulkQueryJob
+type BulkJobType string
+
+const (
+	BulkIngestJob BulkJobType = ingestJobType
+	BulkQueryJob  BulkJobType = queryJobType
+)
+
+// BulkJobInfo is the information Salesforce keeps about a bulk ingest or query job
+type BulkJobInfo struct {
+	Id                      string  `json:"id"`
+	Operation               string  `json:"operation"`
+	Object                  string  `json:"object"`
+	CreatedById             string  `json:"createdById"`
+	CreatedDate             string  `json:"createdDate"`
+	SystemModstamp          string  `json:"systemModstamp"`
+	State                   string  `json:"state"`
+	ExternalIdFieldName     string  `json:"externalIdFieldName"`
+	ConcurrencyMode         string  `json:"concurrencyMode"`
+	ContentType             string  `json:"contentType"`
+	ApiVersion              float64 `json:"apiVersion"`
+	JobType                 string  `json:"jobType"` // e.g. V2Ingest, V2Query or Classic
+	LineEnding              string  `json:"lineEnding"`
+	ColumnDelimiter         string  `json:"columnDelimiter"`
+	NumberRecordsProcessed  int     `json:"numberRecordsProcessed"`
+	NumberRecordsFailed     int     `json:"numberRecordsFailed"`
+	Retries                 int     `json:"retries"`
+	TotalProcessingTime     int64   `json:"totalProcessingTime"` // milliseconds
+	ApiActiveProcessingTime int64   `json:"apiActiveProcessingTime"`
+	ApexProcessingTime      int64   `json:"apexProcessingTime"`
+	IsPkChunkingSupported   bool    `json:"isPkChunkingSupported"`
+	ErrorMessage            string  `json:"errorMessage"`
+}
+
+// BulkJobFilter selects the jobs returned by ListBulkJobs. Empty fields do not filter.
+type BulkJobFilter struct {
+	JobType             BulkJobType // ingest or query jobs, BulkIngestJob when empty
+	APIJobType          string      // jobType of the jobs, e.g. V2Ingest, V2Query or Classic
+	ConcurrencyMode     string      // e.g. parallel
+	IsPkChunkingEnabled *bool
+}
+
+type bulkJobList struct {
+	Done           bool          `json:"done"`
+	Records        []BulkJobInfo `json:"records"`
+	NextRecordsUrl string        `json:"nextRecordsUrl"`
+}
+
+func validateBulkJobType(jobType BulkJobType) error {
+	if jobType != BulkIngestJob && jobType != BulkQueryJob {
+		return fmt.Errorf("invalid bulk job type %q, use BulkIngestJob or BulkQueryJob", jobType)
+	}
+	return nil
+}
+
+// doListBulkJobs gets the jobs that match filter, following nextRecordsUrl until every page is read
+func doListBulkJobs(
+	ctx context.Context,
+	sf *Salesforce,
+	filter BulkJobFilter,
+) ([]BulkJobInfo, error) {
+	if filter.JobType == "" {
+		filter.JobType = BulkIngestJob
+	}
+	if err := validateBulkJobType(filter.JobType); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	if filter.APIJobType != "" {
+		params.Set("jobType", filter.APIJobType)
+	}
+	if filter.ConcurrencyMode != "" {
+		params.Set("concurrencyMode", filter.ConcurrencyMode)
+	}
+	if filter.IsPkChunkingEnabled != nil {
+		params.Set("isPkChunkingEnabled", strconv.FormatBool(*filter.IsPkChunkingEnabled))
+	}
+	uri := "/jobs/" + string(filter.JobType)
+	if len(params) > 0 {
+		uri += "?" + params.Encode()
+	}
+
+	jobs := []BulkJobInfo{}
+	for uri != "" {
+		resp, err := doRequest(ctx, sf.auth, sf.config, requestPayload{
+			method:   http.MethodGet,
+			uri:      uri,
+			content:  jsonType,
+			compress: sf.config.compressionHeaders,
+		})
+		if err != nil {
+			return nil, err
+		}
+		list := bulkJobList{}
+		decodeErr := json.NewDecoder(resp.Body).Decode(&list)
+		_ = resp.Body.Close()
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		jobs = append(jobs, list.Records...)
+
+		uri = ""
+		if !list.Done && list.NextRecordsUrl != "" {
+			uri = strings.TrimPrefix(list.NextRecordsUrl, sf.config.dataPath())
+		}
+	}
+	return jobs, nil
+}
+
+// doBulkJobRequest sends a request for a single job and decodes the job info in the response
+func doBulkJobRequest(
+	ctx context.Context,
+	sf *Salesforce,
+	method string,
+	jobType BulkJobType,
+	bulkJobId string,
+	body string,
+) (BulkJobInfo, error) {
+	if err := validateBulkJobType(jobType); err != nil {
+		return BulkJobInfo{}, err
+	}
+	if bulkJobId == "" {
+		return BulkJobInfo{}, errors.New("bulk job id is required")
+	}
+	resp, err := doRequest(ctx, sf.auth, sf.config, requestPayload{
+		method:   method,
+		uri:      "/jobs/" + string(jobType) + "/" + bulkJobId,
+		content:  jsonType,
+		body:     body,
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return BulkJobInfo{}, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode == http.StatusNoContent {
+		return BulkJobInfo{}, nil
+	}
+
+	job := BulkJobInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return BulkJobInfo{}, err
+	}
+	return job, nil
+}
+
+func getJobRecordResults(
+	ctx context.Context,
+	sf *Salesforce,
+	bulkJobResults BulkJobResults,
+) (BulkJobResults, error) {
+	format := csvFormat{ColumnDelimiter: bulkJobResults.ColumnDelimiter}
+	successfulRecords, err := getBulkJobRecords(
+		ctx,
+		sf,
+		bulkJobResults.Id,
+		successfulResults,
+		format,
+	)
 	if err != nil {
 		return bulkJobResults, fmt.Errorf("failed to get SuccessfulRecords: %w", err)
 	}
 	bulkJobResults.SuccessfulRecords = successfulRecords
-	failedRecords, err := getBulkJobRecords(sf, bulkJobResults.Id, failedResults)
+	bulkJobResults.Successful = newBulkRecordResults(successfulRecords)
+	failedRecords, err := getBulkJobRecords(ctx, sf, bulkJobResults.Id, failedResults, format)
 	if err != nil {
 		return bulkJobResults, fmt.Errorf("failed to get FailedRecords: %w", err)
 	}
 	bulkJobResults.FailedRecords = failedRecords
+	bulkJobResults.Failed = newBulkRecordResults(failedRecords)
+	unprocessedRecords, err := getBulkJobRecords(
+		ctx,
+		sf,
+		bulkJobResults.Id,
+		unprocessedResults,
+		format,
+	)
+	if err != nil {
+		return bulkJobResults, fmt.Errorf("failed to get UnprocessedRecords: %w", err)
+	}
+	bulkJobResults.UnprocessedRecords = unprocessedRecords
+	bulkJobResults.Unprocessed = newBulkRecordResults(unprocessedRecords)
 	return bulkJobResults, err
 }
 
+// newBulkRecordResults separates the sf__ columns of result rows from the uploaded values
+func newBulkRecordResults(records []map[string]any) []BulkRecordResult {
+	if len(records) == 0 {
+		return nil
+	}
+	results := make([]BulkRecordResult, 0, len(records))
+	for _, record := range records {
+		result := BulkRecordResult{Fields: map[string]string{}, Index: -1}
+		for column, value := range record {
+			stringValue, _ := value.(string)
+			switch column {
+			case "sf__Id":
+				result.Id = stringValue
+			case "sf__Created":
+				result.Created = stringValue == "true"
+			case "sf__Error":
+				result.Error = stringValue
+			default:
+				result.Fields[column] = stringValue
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
 func getBulkJobRecords(
+	ctx context.Context,
 	sf *Salesforce,
 	bulkJobId string,
 	resultType string,
+	format csvFormat,
 ) ([]map[string]any, error) {
-	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+	resp, err := doRequest(ctx, sf.auth, sf.config, requestPayload{
 		method:   http.MethodGet,
 		uri:      "/jobs/ingest/" + bulkJobId + "/" + resultType,
 		content:  jsonType,
@@ -183,7 +486,7 @@ func getBulkJobRecords(
 	if err != nil {
 		return nil, err
 	}
-	reader := csv.NewReader(resp.Body)
+	reader := format.csvReader(resp.Body)
 	results, err := csvToMap(*reader)
 	if err != nil {
 		return nil, err
@@ -192,69 +495,107 @@ func getBulkJobRecords(
 	return results, nil
 }
 
+// BulkJobProgress is reported to the WithBulkProgress callback on every poll of a bulk job
+type BulkJobProgress struct {
+	JobId                  string
+	JobType                BulkJobType
+	State                  string
+	PreviousState          string // state at the previous poll, empty on the first poll
+	NumberRecordsProcessed int
+	NumberRecordsFailed    int
+	Elapsed                time.Duration // time since the wait for the job started
+}
+
 func waitForJobResultsAsync(
+	ctx context.Context,
 	sf *Salesforce,
 	bulkJobId string,
 	jobType string,
 	interval time.Duration,
 	c chan error,
 ) {
-	err := pollUntilContextTimeout(
-		context.Background(),
-		interval,
-		sf.config.bulkPollTimeout,
-		func(context.Context) (bool, error) {
-			bulkJob, reqErr := getJobResults(sf, jobType, bulkJobId)
-			if reqErr != nil {
-				return true, reqErr
-			}
-			return isBulkJobDone(bulkJob)
-		},
-	)
+	err := waitForJobResults(ctx, sf, bulkJobId, jobType, interval)
+	if err != nil {
+		err = fmt.Errorf("bulk job %s: %w", bulkJobId, err)
+	}
 	c <- err
 }
 
+// waitForBulkJobs waits for every job to finish, returning the errors of the jobs that failed joined together
+func waitForBulkJobs(ctx context.Context, sf *Salesforce, jobIds []string, jobType string) error {
+	c := make(chan error, len(jobIds))
+	for _, id := range jobIds {
+		go waitForJobResultsAsync(ctx, sf, id, jobType, sf.config.bulkPollInterval, c)
+	}
+	var jobErrors error
+	for range jobIds {
+		jobErrors = errors.Join(jobErrors, <-c)
+	}
+	return jobErrors
+}
+
+// waitForJobResults polls a job until it is done, starting at interval and backing off up to the
+// configured maximum poll interval, and reports the progress of the job on every poll
 func waitForJobResults(
+	ctx context.Context,
 	sf *Salesforce,
 	bulkJobId string,
 	jobType string,
 	interval time.Duration,
 ) error {
+	start := time.Now()
+	previousState := ""
 	err := pollUntilContextTimeout(
-		context.Background(),
+		ctx,
 		interval,
+		sf.config.bulkPollMaxInterval,
 		sf.config.bulkPollTimeout,
-		func(context.Context) (bool, error) {
-			bulkJob, reqErr := getJobResults(sf, jobType, bulkJobId)
+		func(ctx context.Context) (bool, error) {
+			bulkJob, reqErr := getJobResults(ctx, sf, jobType, bulkJobId)
 			if reqErr != nil {
 				return true, reqErr
 			}
-			return isBulkJobDone(bulkJob)
+			if sf.config.bulkProgress != nil {
+				sf.config.bulkProgress(BulkJobProgress{
+					JobId:                  bulkJobId,
+					JobType:                BulkJobType(jobType),
+					State:                  bulkJob.State,
+					PreviousState:          previousState,
+					NumberRecordsProcessed: bulkJob.NumberRecordsProcessed,
+					NumberRecordsFailed:    bulkJob.NumberRecordsFailed,
+					Elapsed:                time.Since(start),
+				})
+			}
+			previousState = bulkJob.State
+			return isBulkJobDone(bulkJob, jobType)
 		},
 	)
 	return err
 }
 
+// pollUntilContextTimeout calls checkFn until it is done, waiting interval before the first call
+// and doubling the wait after every call up to maxInterval
 func pollUntilContextTimeout(
 	ctx context.Context,
 	interval time.Duration,
+	maxInterval time.Duration,
 	timeout time.Duration,
 	checkFn func(context.Context) (bool, error),
 ) error {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 
-		case <-ticker.C:
-			// this handles the race condition where the context timed out at the same time as the ticker fired, and
-			// the pseudo-random behavior of select picked the ticker case. Unlikely, but possible.
+		case <-timer.C:
+			// this handles the race condition where the context timed out at the same time as the timer fired, and
+			// the pseudo-random behavior of select picked the timer case. Unlikely, but possible.
 			if ctx.Err() != nil {
 				return ctx.Err()
 			}
@@ -266,109 +607,263 @@ func pollUntilContextTimeout(
 			if done {
 				return nil
 			}
+			if interval < maxInterval {
+				interval = min(interval*2, maxInterval)
+			}
+			timer.Reset(interval)
 		}
 	}
 }
 
-func isBulkJobDone(bulkJob BulkJobResults) (bool, error) {
+// isBulkJobFinished reports whether a job has stopped, keeping the results of the records it processed
+func isBulkJobFinished(state string) bool {
+	return state == jobStateJobComplete || state == jobStateFailed || state == jobStateAborted
+}
+
+func isBulkJobDone(bulkJob BulkJobResults, jobType string) (bool, error) {
 	if bulkJob.State == jobStateJobComplete || bulkJob.State == jobStateFailed {
 		if bulkJob.ErrorMessage != "" {
-			return true, errors.New(bulkJob.ErrorMessage)
+			return true, newBulkJobError(bulkJob, jobType, bulkJob.ErrorMessage)
 		}
 		return true, nil
 	}
 	if bulkJob.State == jobStateAborted {
-		return true, errors.New("bulk job aborted")
+		return true, newBulkJobError(bulkJob, jobType, "bulk job aborted")
 	}
 	return false, nil
 }
 
-func getQueryJobResults(
+// newBulkJobError reports a failed or aborted job as an *APIError for the job info request that observed it
+func newBulkJobError(bulkJob BulkJobResults, jobType string, message string) *APIError {
+	return &APIError{
+		StatusCode: http.StatusOK,
+		Errors:     []SalesforceErrorMessage{{Message: message}},
+		Method:     http.MethodGet,
+		URI:        "/jobs/" + jobType + "/" + bulkJob.Id,
+	}
+}
+
+type queryResultsPage struct {
+	body            io.ReadCloser
+	numberOfRecords int
+	locator         string // locator of the following page, "null" after the last page
+	err             error
+}
+
+type queryResultsFetch struct {
+	locator chan string // locator of the following page once the headers arrive, empty after the last page
+	page    chan queryResultsPage
+}
+
+// queryResultsPager returns the results pages of a query job in order. With a concurrency above one,
+// the following pages are requested as soon as their locators are known and read into memory
+// while the current page is processed.
+type queryResultsPager struct {
+	ctx         context.Context
+	sf          *Salesforce
+	uri         string
+	first       string // locator of the first page to request, empty for the first page of the results
+	maxRecords  int
+	concurrency int
+	pending     []queryResultsFetch // requested pages that have not been returned, in order
+	tail        *queryResultsFetch  // most recently requested page
+	last        bool                // whether the last page has been requested
+}
+
+func newQueryResultsPager(
+	ctx context.Context,
 	sf *Salesforce,
 	bulkJobId string,
 	locator string,
-) (bulkJobQueryResults, error) {
-	uri := "/jobs/query/" + bulkJobId + "/results"
-	if locator != "" {
-		uri = uri + "/?locator=" + locator
+	options bulkJobOptions,
+) *queryResultsPager {
+	return &queryResultsPager{
+		ctx:         ctx,
+		sf:          sf,
+		uri:         "/jobs/query/" + bulkJobId + "/results",
+		first:       locator,
+		maxRecords:  options.maxRecords,
+		concurrency: max(options.concurrency, 1),
 	}
-	resp, err := doRequest(
-		sf.auth,
-		sf.config,
-		requestPayload{
-			method:   http.MethodGet,
-			uri:      uri,
-			content:  jsonType,
-			compress: sf.config.compressionHeaders,
-		},
-	)
-	if err != nil {
-		return bulkJobQueryResults{}, err
+}
+
+// next returns the next page, or false after the last one. The body of the page must be closed.
+func (p *queryResultsPager) next() (queryResultsPage, bool) {
+	if p.tail == nil {
+		if p.first == lastPageLocator {
+			return queryResultsPage{}, false
+		}
+		p.request(p.first)
+	}
+	for len(p.pending) < p.concurrency && !p.last {
+		locator := <-p.tail.locator
+		if locator == "" {
+			p.last = true
+			break
+		}
+		p.request(locator)
 	}
+	if len(p.pending) == 0 {
+		return queryResultsPage{}, false
+	}
+	page := <-p.pending[0].page
+	p.pending = p.pending[1:]
+	if page.err != nil {
+		p.last = true
+		p.pending = nil // the pages after a failed one are discarded
+	}
+	return page, true
+}
 
-	reader := csv.NewReader(resp.Body)
-	records, readErr := reader.ReadAll()
-	if readErr != nil {
-		return bulkJobQueryResults{}, readErr
+// close discards the pages that have been requested but not returned
+func (p *queryResultsPager) close() {
+	for _, fetch := range p.pending {
+		if page := <-fetch.page; page.body != nil {
+			_ = page.body.Close()
+		}
 	}
-	numberOfRecords, _ := strconv.Atoi(resp.Header["Sforce-Numberofrecords"][0])
-	locator = ""
-	if resp.Header["Sforce-Locator"][0] != "null" {
-		locator = resp.Header["Sforce-Locator"][0]
+	p.pending = nil
+	p.last = true
+}
+
+func (p *queryResultsPager) request(locator string) {
+	fetch := queryResultsFetch{
+		locator: make(chan string, 1),
+		page:    make(chan queryResultsPage, 1),
 	}
+	p.pending = append(p.pending, fetch)
+	p.tail = &fetch
 
-	queryResults := bulkJobQueryResults{
-		NumberOfRecords: numberOfRecords,
-		Locator:         locator,
-		Data:            records,
+	params := url.Values{}
+	if locator != "" {
+		params.Set("locator", locator)
 	}
+	if p.maxRecords > 0 {
+		params.Set("maxRecords", strconv.Itoa(p.maxRecords))
+	}
+	uri := p.uri
+	if len(params) > 0 {
+		uri += "/?" + params.Encode()
+	}
+	buffered := p.concurrency > 1
+
+	go func() {
+		resp, err := doRequest(p.ctx, p.sf.auth, p.sf.config, requestPayload{
+			method:   http.MethodGet,
+			uri:      uri,
+			content:  jsonType,
+			compress: p.sf.config.compressionHeaders,
+		})
+		if err != nil {
+			fetch.locator <- ""
+			fetch.page <- queryResultsPage{err: err}
+			return
+		}
+		page := queryResultsPage{body: resp.Body, locator: resp.Header.Get("Sforce-Locator")}
+		if page.locator == "" {
+			page.locator = lastPageLocator
+		}
+		if page.locator == lastPageLocator {
+			fetch.locator <- ""
+		} else {
+			fetch.locator <- page.locator
+		}
 
-	return queryResults, nil
+		page.numberOfRecords, _ = strconv.Atoi(resp.Header.Get("Sforce-Numberofrecords"))
+		if buffered {
+			// read the page now so that the connection is not held until the page is processed
+			data, readErr := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			page.body, page.err = io.NopCloser(bytes.NewReader(data)), readErr
+		}
+		fetch.page <- page
+	}()
 }
 
-func collectQueryResults(sf *Salesforce, bulkJobId string) ([][]string, error) {
-	queryResults, resultsErr := getQueryJobResults(sf, bulkJobId, "")
-	if resultsErr != nil {
-		return nil, resultsErr
+// writeQueryResults writes the results of the query jobs to w in order, one page at a time,
+// keeping only the first header row
+func writeQueryResults(
+	ctx context.Context,
+	sf *Salesforce,
+	chunks []*bulkQueryChunk,
+	w io.Writer,
+	options bulkJobOptions,
+) error {
+	format := options.format()
+	writer := format.csvWriter(w)
+	headerWritten := false
+	for _, chunk := range chunks {
+		if err := waitForQueryChunk(ctx, chunk, len(chunks)); err != nil {
+			return err
+		}
+		pager := newQueryResultsPager(ctx, sf, chunk.jobId, chunk.locator, options)
+		for {
+			page, ok := pager.next()
+			if !ok {
+				break
+			}
+			if page.err != nil {
+				return page.err
+			}
+			rows, copyErr := copyCSVRows(format.csvReader(page.body), writer, headerWritten)
+			_ = page.body.Close()
+			if copyErr != nil {
+				return copyErr
+			}
+			headerWritten = headerWritten || rows > 0
+		}
 	}
-	records := queryResults.Data
-	for queryResults.Locator != "" {
-		queryResults, resultsErr = getQueryJobResults(sf, bulkJobId, queryResults.Locator)
-		if resultsErr != nil {
-			return nil, resultsErr
+	return nil
+}
+
+// copyCSVRows copies the rows read by reader to writer, optionally skipping the header row,
+// and returns the number of rows read
+func copyCSVRows(reader *csv.Reader, writer *csv.Writer, skipHeader bool) (int, error) {
+	reader.ReuseRecord = true
+	rows := 0
+	for ; ; rows++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return rows, err
+		}
+		if rows == 0 && skipHeader {
+			continue
+		}
+		if err := writer.Write(row); err != nil {
+			return rows, err
 		}
-		records = append(
-			records,
-			queryResults.Data[1:]...) // don't include headers in subsequent batches
 	}
-	return records, nil
+	writer.Flush()
+	return rows, writer.Error()
 }
 
-func mapsToCSV(maps []map[string]any) (string, error) {
+func mapsToCSV(records []map[string]any, format csvFormat) (string, error) {
 	var buf bytes.Buffer
-	w := csv.NewWriter(&buf)
+	w := format.csvWriter(&buf)
 	var headers []string
 
-	if len(maps) > 0 {
-		headers = make([]string, 0, len(maps[0]))
-		for header := range maps[0] {
-			headers = append(headers, header)
+	if len(records) > 0 {
+		// structs tagged omitempty leave out empty fields, so every record can have a different set of keys
+		columns := map[string]bool{}
+		for _, m := range records {
+			for header := range m {
+				columns[header] = true
+			}
 		}
+		headers = slices.Sorted(maps.Keys(columns))
 		err := w.Write(headers)
 		if err != nil {
 			return "", err
 		}
 	}
 
-	for _, m := range maps {
+	for _, m := range records {
 		row := make([]string, 0, len(headers))
 		for _, header := range headers {
-			val := m[header]
-			if val == nil {
-				row = append(row, "")
-			} else {
-				row = append(row, fmt.Sprintf("%v", val))
-			}
+			row = append(row, csvValue(m[header]))
 		}
 		err := w.Write(row)
 		if err != nil {
@@ -384,6 +879,13 @@ func mapsToCSV(maps []map[string]any) (string, error) {
 	return buf.String(), nil
 }
 
+func csvValue(val any) string {
+	if val == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", val)
+}
+
 func csvToMap(reader csv.Reader) ([]map[string]any, error) {
 	records, readErr := reader.ReadAll()
 	if readErr != nil {
@@ -444,21 +946,25 @@ func writeCSVFile(filePath string, data [][]string) error {
 }
 
 func constructBulkJobRequest(
+	ctx context.Context,
 	sf *Salesforce,
 	sObjectName string,
 	operation string,
 	fieldName string,
 	assignmentRuleId string,
+	format csvFormat,
 ) (bulkJob, error) {
 	jobReq := bulkJobCreationRequest{
 		Object:              sObjectName,
 		Operation:           operation,
 		ExternalIdFieldName: fieldName,
 		AssignmentRuleId:    assignmentRuleId,
+		ColumnDelimiter:     format.ColumnDelimiter,
+		LineEnding:          format.LineEnding,
 	}
 	body, _ := json.Marshal(jobReq)
 
-	job, jobCreationErr := createBulkJob(sf, ingestJobType, body)
+	job, jobCreationErr := createBulkJob(ctx, sf, ingestJobType, body)
 	if jobCreationErr != nil {
 		return bulkJob{}, jobCreationErr
 	}
@@ -473,6 +979,7 @@ func constructBulkJobRequest(
 }
 
 func doBulkJob(
+	ctx context.Context,
 	sf *Salesforce,
 	sObjectName string,
 	fieldName string,
@@ -481,8 +988,9 @@ func doBulkJob(
 	batchSize int,
 	waitForResults bool,
 	assignmentRuleId string,
+	format csvFormat,
 ) ([]string, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+	recordMap, err := convertToSliceOfMaps(records, operation)
 	if err != nil {
 		return []string{}, err
 	}
@@ -500,40 +1008,39 @@ func doBulkJob(
 		recordMap = remaining
 
 		job, constructJobErr := constructBulkJobRequest(
+			ctx,
 			sf,
 			sObjectName,
 			operation,
 			fieldName,
 			assignmentRuleId,
+			format,
 		)
 		if constructJobErr != nil {
 			return jobIds, constructJobErr
 		}
 		jobIds = append(jobIds, job.Id)
 
-		data, convertErr := mapsToCSV(batch)
+		data, convertErr := mapsToCSV(batch, format)
 		if convertErr != nil {
 			return jobIds, convertErr
 		}
 
-		uploadErr := uploadJobData(sf, data, job)
+		uploadErr := uploadJobData(ctx, sf, data, job)
 		if uploadErr != nil {
 			return jobIds, uploadErr
 		}
 	}
 
 	if waitForResults {
-		c := make(chan error, len(jobIds))
-		for _, id := range jobIds {
-			go waitForJobResultsAsync(sf, id, ingestJobType, (time.Second / 2), c)
-		}
-		jobErrors = <-c
+		jobErrors = waitForBulkJobs(ctx, sf, jobIds, ingestJobType)
 	}
 
 	return jobIds, jobErrors
 }
 
 func doBulkJobWithFile(
+	ctx context.Context,
 	sf *Salesforce,
 	sObjectName string,
 	fieldName string,
@@ -542,102 +1049,574 @@ func doBulkJobWithFile(
 	batchSize int,
 	waitForResults bool,
 	assignmentRuleId string,
+	format csvFormat,
 ) ([]string, error) {
-	var jobErrors error
-	var jobIds []string
-
-	records, readErr := readCSVFile(filePath)
-	if readErr != nil {
-		return jobIds, readErr
+	file, fileErr := appFs.Open(filePath)
+	if fileErr != nil {
+		return nil, fileErr
 	}
+	defer func() {
+		_ = file.Close() // Ignore error since the data has been uploaded
+	}()
 
-	headers := records[0]
-	records = records[1:]
-	for len(records) > 0 {
-		var batch [][]string
-		var remaining [][]string
-		if len(records) > batchSize {
-			batch, remaining = records[:batchSize], records[batchSize:]
-		} else {
-			batch = records
-		}
-		records = remaining
+	return doBulkJobWithReader(
+		ctx,
+		sf,
+		sObjectName,
+		fieldName,
+		operation,
+		file,
+		batchSize,
+		waitForResults,
+		assignmentRuleId,
+		format,
+	)
+}
 
+// doBulkJobWithReader streams CSV data into jobs of up to batchSize rows and bulkUploadSizeMax bytes,
+// holding only one row in memory at a time
+func doBulkJobWithReader(
+	ctx context.Context,
+	sf *Salesforce,
+	sObjectName string,
+	fieldName string,
+	operation string,
+	reader io.Reader,
+	batchSize int,
+	waitForResults bool,
+	assignmentRuleId string,
+	format csvFormat,
+) ([]string, error) {
+	batcher, err := newCSVBatcher(reader, format)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobIds []string
+	for batcher.next() {
 		job, constructJobErr := constructBulkJobRequest(
+			ctx,
 			sf,
 			sObjectName,
 			operation,
 			fieldName,
 			assignmentRuleId,
+			format,
 		)
 		if constructJobErr != nil {
-			jobErrors = errors.Join(jobErrors, constructJobErr)
+			return jobIds, constructJobErr
+		}
+		jobIds = append(jobIds, job.Id)
+
+		pr, pw := io.Pipe()
+		written := make(chan error, 1)
+		go func() {
+			writeErr := batcher.writeBatch(pw, batchSize, bulkUploadSizeMax)
+			_ = pw.CloseWithError(writeErr)
+			written <- writeErr
+		}()
+		uploadErr := uploadJobStream(ctx, sf, pr, job)
+		_ = pr.Close() // stops the writer when the upload ended before reading all of the batch
+		writeErr := <-written
+		if writeErr != nil && !errors.Is(writeErr, io.ErrClosedPipe) {
+			return jobIds, writeErr
+		}
+		if uploadErr != nil {
+			return jobIds, uploadErr
+		}
+	}
+	if batcher.err != nil {
+		return jobIds, batcher.err
+	}
+
+	if waitForResults {
+		return jobIds, waitForBulkJobs(ctx, sf, jobIds, ingestJobType)
+	}
+	return jobIds, nil
+}
+
+// csvBatcher splits CSV data into job uploads that each start with the header row
+type csvBatcher struct {
+	reader  *csv.Reader
+	format  csvFormat
+	header  []byte // encoded header row
+	pending []byte // encoded row that has been read but not written
+	err     error
+}
+
+func newCSVBatcher(reader io.Reader, format csvFormat) (*csvBatcher, error) {
+	batcher := &csvBatcher{reader: format.csvReader(reader), format: format}
+	header, err := batcher.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv data has no header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+	batcher.header, err = encodeCSVRow(header, format)
+	if err != nil {
+		return nil, err
+	}
+	return batcher, nil
+}
+
+// next reads the next row unless one is pending, returning false at the end of the data or on error
+func (b *csvBatcher) next() bool {
+	if b.pending != nil {
+		return true
+	}
+	if b.err != nil {
+		return false
+	}
+	row, err := b.reader.Read()
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			b.err = err
+		}
+		return false
+	}
+	b.pending, b.err = encodeCSVRow(row, b.format)
+	return b.err == nil
+}
+
+// writeBatch writes the header and up to maxRows rows to w, stopping before the batch exceeds maxBytes
+func (b *csvBatcher) writeBatch(w io.Writer, maxRows int, maxBytes int) error {
+	if _, err := w.Write(b.header); err != nil {
+		return err
+	}
+	size := len(b.header)
+	for rows := 0; rows < maxRows && b.next(); rows++ {
+		if size+len(b.pending) > maxBytes {
+			if rows == 0 {
+				return fmt.Errorf(
+					"csv row of %d bytes exceeds the bulk upload limit",
+					len(b.pending),
+				)
+			}
 			break
 		}
+		if _, err := w.Write(b.pending); err != nil {
+			return err
+		}
+		size += len(b.pending)
+		b.pending = nil
+	}
+	return b.err
+}
+
+func encodeCSVRow(row []string, format csvFormat) ([]byte, error) {
+	var buf bytes.Buffer
+	w := format.csvWriter(&buf)
+	if err := w.Write(row); err != nil {
+		return nil, err
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// doInsertBulkWithIds inserts the records that records points to, waits for the jobs to complete
+// and writes the created Ids and record errors back into the records
+func doInsertBulkWithIds(
+	ctx context.Context,
+	sf *Salesforce,
+	sObjectName string,
+	records any,
+	batchSize int,
+	format csvFormat,
+) ([]string, error) {
+	recordMap, err := convertToSliceOfMaps(
+		reflect.ValueOf(records).Elem().Interface(),
+		insertOperation,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for i := range recordMap {
+		delete(recordMap[i], "Id")
+	}
+
+	type uploadedBatch struct {
+		jobId   string
+		start   int
+		records []map[string]any
+	}
+	var batches []uploadedBatch
+	var jobIds []string
+	for start := 0; start < len(recordMap); start += batchSize {
+		batch := recordMap[start:min(start+batchSize, len(recordMap))]
+		job, constructJobErr := constructBulkJobRequest(
+			ctx,
+			sf,
+			sObjectName,
+			insertOperation,
+			"",
+			"",
+			format,
+		)
+		if constructJobErr != nil {
+			return jobIds, constructJobErr
+		}
 		jobIds = append(jobIds, job.Id)
 
-		var buf bytes.Buffer
-		w := csv.NewWriter(&buf)
-		batch = append([][]string{headers}, batch...)
-		if err := w.WriteAll(batch); err != nil {
-			jobErrors = errors.Join(jobErrors, err)
+		data, convertErr := mapsToCSV(batch, format)
+		if convertErr != nil {
+			return jobIds, convertErr
+		}
+		if uploadErr := uploadJobData(ctx, sf, data, job); uploadErr != nil {
+			return jobIds, uploadErr
+		}
+		batches = append(batches, uploadedBatch{jobId: job.Id, start: start, records: batch})
+	}
+
+	waitErr := waitForBulkJobs(ctx, sf, jobIds, ingestJobType)
+	results := make([]SalesforceResult, len(recordMap))
+	for _, batch := range batches {
+		if waitErr != nil {
+			// the records created by the jobs that finished are still assigned, so a retry does not duplicate them
+			job, infoErr := getJobResults(ctx, sf, ingestJobType, batch.jobId)
+			if infoErr != nil || !isBulkJobFinished(job.State) {
+				continue
+			}
+		}
+		jobResults, resultsErr := getJobRecordResults(
+			ctx,
+			sf,
+			BulkJobResults{Id: batch.jobId, ColumnDelimiter: format.ColumnDelimiter},
+		)
+		if resultsErr != nil {
+			return jobIds, errors.Join(waitErr, resultsErr)
+		}
+		indexBulkResults(batch.records, batch.start, &jobResults)
+		for _, row := range jobResults.Successful {
+			if row.Index >= 0 {
+				results[row.Index] = SalesforceResult{Id: row.Id, Success: true}
+			}
+		}
+		for _, row := range jobResults.Failed {
+			if row.Index >= 0 {
+				results[row.Index] = SalesforceResult{
+					Id:     row.Id,
+					Errors: []SalesforceErrorMessage{parseBulkRecordError(row.Error)},
+				}
+			}
+		}
+	}
+
+	return jobIds, errors.Join(waitErr, assignResults(records, results))
+}
+
+// MatchBulkResults sets the Index of each result row to the position of its record in records.
+// results are the results of the job Ids returned by InsertBulk, UpdateBulk, UpsertBulk or DeleteBulk,
+// in the same order, and records and batchSize are the arguments the jobs were created with.
+func MatchBulkResults(records any, batchSize int, results []BulkJobResults) error {
+	if batchSize < 1 {
+		return errors.New("batch size must be at least 1")
+	}
+	recordMap, err := convertToSliceOfMaps(records, "")
+	if err != nil {
+		return err
+	}
+	for i := range results {
+		start := i * batchSize
+		if start >= len(recordMap) {
 			break
 		}
-		w.Flush()
-		writeErr := w.Error()
-		if writeErr != nil {
-			jobErrors = errors.Join(jobErrors, writeErr)
+		indexBulkResults(recordMap[start:min(start+batchSize, len(recordMap))], start, &results[i])
+	}
+	return nil
+}
+
+// indexBulkResults sets the Index of the result rows of the job that uploaded batch. Result rows repeat the
+// uploaded values but are not in upload order, so they are matched by value, in order for identical records.
+func indexBulkResults(batch []map[string]any, offset int, jobResults *BulkJobResults) {
+	groups := [][]BulkRecordResult{
+		jobResults.Successful,
+		jobResults.Failed,
+		jobResults.Unprocessed,
+	}
+	var headers []string
+	for _, group := range groups {
+		if len(group) > 0 {
+			headers = slices.Sorted(maps.Keys(group[0].Fields))
 			break
 		}
+	}
+	rowKey := func(value func(header string) string) string {
+		values := make([]string, len(headers))
+		for i, header := range headers {
+			values[i] = value(header)
+		}
+		return strings.Join(values, "\x00")
+	}
 
-		uploadErr := uploadJobData(sf, buf.String(), job)
-		if uploadErr != nil {
-			jobErrors = errors.Join(jobErrors, uploadErr)
+	positions := map[string][]int{}
+	for i, record := range batch {
+		key := rowKey(func(header string) string { return csvValue(record[header]) })
+		positions[key] = append(positions[key], offset+i)
+	}
+	for _, group := range groups {
+		for i := range group {
+			key := rowKey(func(header string) string { return group[i].Fields[header] })
+			if matches := positions[key]; len(matches) > 0 {
+				group[i].Index = matches[0]
+				positions[key] = matches[1:]
+			}
 		}
 	}
+}
 
-	if waitForResults {
-		c := make(chan error, len(jobIds))
-		for _, id := range jobIds {
-			go waitForJobResultsAsync(sf, id, ingestJobType, (time.Second / 2), c)
+// parseBulkRecordError splits an sf__Error value such as "REQUIRED_FIELD_MISSING:Required fields are missing"
+// into its status code and message
+func parseBulkRecordError(sfError string) SalesforceErrorMessage {
+	code, message, found := strings.Cut(sfError, ":")
+	if !found {
+		return SalesforceErrorMessage{Message: sfError}
+	}
+	return SalesforceErrorMessage{StatusCode: code, Message: message}
+}
+
+type bulkJobOptions struct {
+	request      bulkQueryJobCreationRequest
+	maxRecords   int      // records per results page, chosen by Salesforce when 0
+	concurrency  int      // results pages downloaded at the same time
+	chunkSize    int      // records per query job with PK chunking, a single job when 0
+	parallelJobs int      // PK chunking query jobs running at the same time
+	queryOnly    []string // options that only apply to bulk queries, rejected by ingest jobs
+}
+
+func newBulkQueryOptions(query string, opts []BulkJobOption) (bulkJobOptions, error) {
+	options := bulkJobOptions{
+		request: bulkQueryJobCreationRequest{
+			Operation: queryJobType,
+			Query:     query,
+		},
+		concurrency: 1,
+	}
+	for _, opt := range opts {
+		if err := opt(&options); err != nil {
+			return bulkJobOptions{}, err
 		}
-		jobErrors = <-c
 	}
+	return options, nil
+}
 
-	return jobIds, jobErrors
+// newBulkIngestOptions returns the format of the CSV data of an ingest job
+func newBulkIngestOptions(opts []BulkJobOption) (csvFormat, error) {
+	options, err := newBulkQueryOptions("", opts)
+	if err != nil {
+		return csvFormat{}, err
+	}
+	if len(options.queryOnly) > 0 {
+		return csvFormat{}, fmt.Errorf("%s only applies to bulk queries", options.queryOnly[0])
+	}
+	return options.format(), nil
+}
+
+// format returns the format of the CSV data and results of the job
+func (o bulkJobOptions) format() csvFormat {
+	return csvFormat{
+		ColumnDelimiter: o.request.ColumnDelimiter,
+		LineEnding:      o.request.LineEnding,
+	}
+}
+
+// BulkJobOption configures a bulk job and the download of its results
+type BulkJobOption func(*bulkJobOptions) error
+
+// WithColumnDelimiter sets the column delimiter of the CSV data and results of a bulk job.
+// The default is ColumnDelimiterComma.
+func WithColumnDelimiter(delimiter ColumnDelimiter) BulkJobOption {
+	return func(options *bulkJobOptions) error {
+		if _, ok := columnDelimiters[delimiter]; !ok {
+			return fmt.Errorf("invalid column delimiter %q", delimiter)
+		}
+		options.request.ColumnDelimiter = delimiter
+		return nil
+	}
+}
+
+// WithLineEnding sets the line ending of the CSV data and results of a bulk job.
+// The default is LineEndingLF.
+func WithLineEnding(lineEnding LineEnding) BulkJobOption {
+	return func(options *bulkJobOptions) error {
+		if lineEnding != LineEndingLF && lineEnding != LineEndingCRLF {
+			return fmt.Errorf("invalid line ending %q", lineEnding)
+		}
+		options.request.LineEnding = lineEnding
+		return nil
+	}
 }
 
-func doQueryBulk(sf *Salesforce, filePath string, query string) error {
-	queryJobReq := bulkQueryJobCreationRequest{
-		Operation: queryJobType,
-		Query:     query,
+// WithQueryAll includes deleted records and archived activities in the results of a bulk query
+func WithQueryAll() BulkJobOption {
+	return func(options *bulkJobOptions) error {
+		options.request.Operation = queryAllOperation
+		options.queryOnly = append(options.queryOnly, "WithQueryAll")
+		return nil
+	}
+}
+
+// WithMaxRecords sets the maximum number of records in each page of bulk query results.
+// Salesforce chooses the page size without this option.
+func WithMaxRecords(maxRecords int) BulkJobOption {
+	return func(options *bulkJobOptions) error {
+		if maxRecords < 1 {
+			return errors.New("max records must be at least 1")
+		}
+		options.maxRecords = maxRecords
+		options.queryOnly = append(options.queryOnly, "WithMaxRecords")
+		return nil
+	}
+}
+
+// WithPKChunking splits a bulk query into query jobs of about chunkSize records each by ranges of Ids,
+// running up to parallelJobs jobs at the same time and retrying the jobs that fail. The results of the
+// jobs are merged in Id order. The query must not use ORDER BY, GROUP BY, LIMIT or OFFSET.
+func WithPKChunking(chunkSize int, parallelJobs int) BulkJobOption {
+	return func(options *bulkJobOptions) error {
+		if chunkSize < 1 {
+			return errors.New("pk chunking chunk size must be at least 1")
+		}
+		if parallelJobs < 1 {
+			return errors.New("pk chunking parallel jobs must be at least 1")
+		}
+		options.chunkSize = chunkSize
+		options.parallelJobs = parallelJobs
+		options.queryOnly = append(options.queryOnly, "WithPKChunking")
+		return nil
+	}
+}
+
+// WithConcurrentDownloads downloads up to n pages of bulk query results at the same time,
+// holding the pages in memory until they are written or decoded in order. The default is 1.
+func WithConcurrentDownloads(n int) BulkJobOption {
+	return func(options *bulkJobOptions) error {
+		if n < 1 {
+			return errors.New("concurrent downloads must be at least 1")
+		}
+		options.concurrency = n
+		options.queryOnly = append(options.queryOnly, "WithConcurrentDownloads")
+		return nil
 	}
-	body, jsonErr := json.Marshal(queryJobReq)
+}
+
+func createBulkQueryJob(
+	ctx context.Context,
+	sf *Salesforce,
+	query string,
+	options bulkJobOptions,
+) (bulkJob, error) {
+	options.request.Query = query
+	body, jsonErr := json.Marshal(options.request)
 	if jsonErr != nil {
-		return jsonErr
+		return bulkJob{}, jsonErr
 	}
 
-	job, jobCreationErr := createBulkJob(sf, queryJobType, body)
+	job, jobCreationErr := createBulkJob(ctx, sf, queryJobType, body)
 	if jobCreationErr != nil {
-		return jobCreationErr
+		return bulkJob{}, jobCreationErr
 	}
 	if job.Id == "" {
 		newErr := errors.New("error creating bulk query job")
-		return newErr
+		return bulkJob{}, newErr
+	}
+	return job, nil
+}
+
+// startBulkQuery creates the query jobs of a query, returning them in the order of their results.
+// Without PK chunking the query runs as a single job that has completed when startBulkQuery returns.
+func startBulkQuery(
+	ctx context.Context,
+	sf *Salesforce,
+	options bulkJobOptions,
+) ([]*bulkQueryChunk, error) {
+	query := options.request.Query
+	if options.chunkSize > 0 {
+		queries, err := pkChunkQueries(ctx, sf, query, options)
+		if err != nil {
+			return nil, err
+		}
+		return startPKChunkJobs(ctx, sf, queries, options), nil
 	}
 
-	pollErr := waitForJobResults(sf, job.Id, queryJobType, (time.Second / 2))
+	job, err := createBulkQueryJob(ctx, sf, query, options)
+	if err != nil {
+		return nil, err
+	}
+	pollErr := waitForJobResults(ctx, sf, job.Id, queryJobType, sf.config.bulkPollInterval)
 	if pollErr != nil {
-		return pollErr
+		return nil, pollErr
 	}
-	records, reqErr := collectQueryResults(sf, job.Id)
-	if reqErr != nil {
-		return reqErr
+	return []*bulkQueryChunk{newCompletedQueryChunk(query, job.Id)}, nil
+}
+
+func newCompletedQueryChunk(query string, jobId string) *bulkQueryChunk {
+	chunk := &bulkQueryChunk{query: query, jobId: jobId, done: make(chan struct{})}
+	close(chunk.done)
+	return chunk
+}
+
+// waitForQueryChunk waits for the job of a chunk to complete, identifying the chunk in errors
+func waitForQueryChunk(ctx context.Context, chunk *bulkQueryChunk, chunks int) error {
+	select {
+	case <-chunk.done:
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	writeErr := writeCSVFile(filePath, records)
+	if chunk.err != nil && chunks > 1 {
+		return fmt.Errorf("query chunk %q: %w", chunk.query, chunk.err)
+	}
+	return chunk.err
+}
+
+func doQueryBulk(
+	ctx context.Context,
+	sf *Salesforce,
+	filePath string,
+	query string,
+	opts ...BulkJobOption,
+) error {
+	options, err := newBulkQueryOptions(query, opts)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx) // stops the chunk jobs that are still running on error
+	defer cancel()
+	chunks, err := startBulkQuery(ctx, sf, options)
+	if err != nil {
+		return err
+	}
+
+	file, fileErr := appFs.Create(filePath)
+	if fileErr != nil {
+		return fileErr
+	}
+	writeErr := writeQueryResults(ctx, sf, chunks, file, options)
+	closeErr := file.Close()
 	if writeErr != nil {
 		return writeErr
 	}
 
-	return nil
+	return closeErr
+}
+
+// doQueryBulkTo runs a bulk query and writes the results to w as each page arrives
+func doQueryBulkTo(
+	ctx context.Context,
+	sf *Salesforce,
+	w io.Writer,
+	query string,
+	opts ...BulkJobOption,
+) error {
+	options, err := newBulkQueryOptions(query, opts)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	chunks, err := startBulkQuery(ctx, sf, options)
+	if err != nil {
+		return err
+	}
+	return writeQueryResults(ctx, sf, chunks, w, options)
 }
@@ -5,10 +5,14 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -91,7 +95,12 @@ func Test_createBulkJob(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := createBulkJob(tt.args.sf, tt.args.jobType, tt.args.body)
+			got, err := createBulkJob(
+				context.Background(),
+				tt.args.sf,
+				tt.args.jobType,
+				tt.args.body,
+			)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("createBulkJob() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -161,7 +170,12 @@ func Test_getJobResults(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getJobResults(tt.args.sf, tt.args.jobType, tt.args.bulkJobId)
+			got, err := getJobResults(
+				context.Background(),
+				tt.args.sf,
+				tt.args.jobType,
+				tt.args.bulkJobId,
+			)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getJobResults() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -238,7 +252,7 @@ func Test_isBulkJobDone(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := isBulkJobDone(tt.args.bulkJob)
+			got, err := isBulkJobDone(tt.args.bulkJob, ingestJobType)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("isBulkJobDone() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -250,73 +264,165 @@ func Test_isBulkJobDone(t *testing.T) {
 	}
 }
 
-func Test_getQueryJobResults(t *testing.T) {
-	csvData := `"col"` + "\n" + `"row"`
+// setupQueryResultsServer serves three pages of query results. The first page is only completed
+// once the second page has been requested, unless the second page is not requested in time.
+func setupQueryResultsServer(
+	failLastPage bool,
+) (*httptest.Server, authentication, func() []string) {
+	var mu sync.Mutex
+	var queries []string
+	secondRequested := make(chan struct{})
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
 		w.Header().Add("Sforce-Numberofrecords", "1")
-		w.Header().Add("Sforce-Locator", "")
-		if _, err := w.Write([]byte(csvData)); err != nil {
-			t.Fatal(err.Error())
+		switch r.URL.Query().Get("locator") {
+		case "":
+			w.Header().Add("Sforce-Locator", "page2")
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			select {
+			case <-secondRequested:
+			case <-time.After(100 * time.Millisecond):
+			}
+			_, _ = w.Write([]byte("\"Id\"\n\"1\"\n"))
+		case "page2":
+			close(secondRequested)
+			w.Header().Add("Sforce-Locator", "page3")
+			_, _ = w.Write([]byte("\"Id\"\n\"2\"\n"))
+		default:
+			if failLastPage {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Header().Add("Sforce-Locator", "null")
+			_, _ = w.Write([]byte("\"Id\"\n\"3\"\n"))
 		}
 	}))
-	sfAuth := authentication{
-		InstanceUrl: server.URL,
-		AccessToken: "accesstokenvalue",
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+	return server, sfAuth, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(queries)
 	}
-	defer server.Close()
-
-	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
-	defer badServer.Close()
+}
 
-	type args struct {
-		sf        *Salesforce
-		bulkJobId string
-		locator   string
-	}
+func Test_queryResultsPager(t *testing.T) {
 	tests := []struct {
-		name    string
-		args    args
-		want    bulkJobQueryResults
-		wantErr bool
+		name         string
+		locator      string
+		options      bulkJobOptions
+		failLastPage bool
+		want         []string
+		wantQueries  []string
+		wantErr      bool
 	}{
 		{
-			name: "get_single_query_job_result",
-			args: args{
-				sf:        buildSalesforceStruct(&sfAuth),
-				bulkJobId: "1234",
-				locator:   "",
-			},
-			want: bulkJobQueryResults{
-				NumberOfRecords: 1,
-				Locator:         "",
-				Data:            [][]string{{"col"}, {"row"}},
-			},
-			wantErr: false,
+			name:        "sequential",
+			options:     bulkJobOptions{concurrency: 1},
+			want:        []string{"\"Id\"\n\"1\"\n", "\"Id\"\n\"2\"\n", "\"Id\"\n\"3\"\n"},
+			wantQueries: []string{"", "locator=page2", "locator=page3"},
 		},
 		{
-			name: "bad_request",
-			args: args{
-				sf:        buildSalesforceStruct(&badSfAuth),
-				bulkJobId: "1234",
-				locator:   "",
+			name:    "concurrent_with_max_records",
+			options: bulkJobOptions{concurrency: 3, maxRecords: 1},
+			want:    []string{"\"Id\"\n\"1\"\n", "\"Id\"\n\"2\"\n", "\"Id\"\n\"3\"\n"},
+			wantQueries: []string{
+				"maxRecords=1",
+				"locator=page2&maxRecords=1",
+				"locator=page3&maxRecords=1",
 			},
-			wantErr: true,
+		},
+		{
+			name:         "failed_page",
+			options:      bulkJobOptions{concurrency: 2},
+			failLastPage: true,
+			want:         []string{"\"Id\"\n\"1\"\n", "\"Id\"\n\"2\"\n"},
+			wantQueries:  []string{"", "locator=page2", "locator=page3"},
+			wantErr:      true,
+		},
+		{
+			name:        "resume_from_locator",
+			locator:     "page2",
+			options:     bulkJobOptions{concurrency: 2},
+			want:        []string{"\"Id\"\n\"2\"\n", "\"Id\"\n\"3\"\n"},
+			wantQueries: []string{"locator=page2", "locator=page3"},
+		},
+		{
+			name:    "resume_after_last_page",
+			locator: "null",
+			options: bulkJobOptions{concurrency: 1},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getQueryJobResults(tt.args.sf, tt.args.bulkJobId, tt.args.locator)
+			server, sfAuth, queries := setupQueryResultsServer(tt.failLastPage)
+			defer server.Close()
+
+			pager := newQueryResultsPager(
+				context.Background(),
+				buildSalesforceStruct(&sfAuth),
+				"750",
+				tt.locator,
+				tt.options,
+			)
+			var got []string
+			var err error
+			for {
+				page, ok := pager.next()
+				if !ok {
+					break
+				}
+				if page.err != nil {
+					err = page.err
+					continue
+				}
+				data, _ := io.ReadAll(page.body)
+				_ = page.body.Close()
+				got = append(got, string(data))
+			}
 			if (err != nil) != tt.wantErr {
-				t.Errorf("getQueryJobResults() error = %v, wantErr %v", err, tt.wantErr)
-				return
+				t.Errorf("queryResultsPager.next() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("getQueryJobResults() = %v, want %v", got, tt.want)
+				t.Errorf("queryResultsPager pages = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(queries(), tt.wantQueries) {
+				t.Errorf("queryResultsPager queries = %q, want %q", queries(), tt.wantQueries)
 			}
 		})
 	}
 }
 
+func Test_queryResultsPager_prefetch(t *testing.T) {
+	server, sfAuth, queries := setupQueryResultsServer(false)
+	defer server.Close()
+
+	pager := newQueryResultsPager(
+		context.Background(),
+		buildSalesforceStruct(&sfAuth),
+		"750",
+		"",
+		bulkJobOptions{concurrency: 2},
+	)
+	start := time.Now()
+	page, ok := pager.next()
+	if !ok || page.err != nil {
+		t.Fatalf("queryResultsPager.next() = %v, %v", page.err, ok)
+	}
+	_ = page.body.Close()
+	if elapsed := time.Since(start); elapsed >= 100*time.Millisecond {
+		t.Errorf(
+			"first page took %v, want the second page to be requested while it downloads",
+			elapsed,
+		)
+	}
+	if len(queries()) != 2 {
+		t.Errorf("queryResultsPager made %d requests after the first page, want 2", len(queries()))
+	}
+}
+
 func Test_mapsToCSV(t *testing.T) {
 	type args struct {
 		maps []map[string]any
@@ -354,10 +460,22 @@ func Test_mapsToCSV(t *testing.T) {
 			want:    "key\n\n",
 			wantErr: false,
 		},
+		{
+			name: "union_of_keys",
+			args: args{
+				maps: []map[string]any{
+					{"Name": "a"},
+					{"Name": "b", "Phone": "555"},
+					{"Industry": "Tech"},
+				},
+			},
+			want:    "Industry,Name,Phone\n,a,\n,b,555\nTech,,\n",
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := mapsToCSV(tt.args.maps)
+			got, err := mapsToCSV(tt.args.maps, csvFormat{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("mapsToCSV() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -458,11 +576,13 @@ func Test_constructBulkJobRequest(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := constructBulkJobRequest(
+				context.Background(),
 				tt.args.sf,
 				tt.args.sObjectName,
 				tt.args.operation,
 				tt.args.fieldName,
 				"",
+				csvFormat{},
 			)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("constructBulkJobRequest() error = %v, wantErr %v", err, tt.wantErr)
@@ -668,6 +788,7 @@ func Test_doBulkJob(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := doBulkJob(
+				context.Background(),
 				tt.args.sf,
 				tt.args.sObjectName,
 				tt.args.fieldName,
@@ -676,6 +797,7 @@ func Test_doBulkJob(t *testing.T) {
 				tt.args.batchSize,
 				tt.args.waitForResults,
 				"",
+				csvFormat{},
 			)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doBulkJob() error = %v, wantErr %v", err, tt.wantErr)
@@ -748,6 +870,7 @@ func Test_waitForJobResultsAsync(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			go waitForJobResultsAsync(
+				context.Background(),
 				tt.args.sf,
 				tt.args.bulkJobId,
 				tt.args.jobType,
@@ -819,6 +942,7 @@ func Test_waitForJobResults(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			err := waitForJobResults(
+				context.Background(),
 				tt.args.sf,
 				tt.args.bulkJobId,
 				tt.args.jobType,
@@ -843,6 +967,7 @@ func Test_waitForJobResults_UsesConfiguredTimeout(t *testing.T) {
 	sf.config.bulkPollTimeout = 2 * time.Millisecond
 
 	err := waitForJobResults(
+		context.Background(),
 		sf,
 		"1234",
 		ingestJobType,
@@ -867,6 +992,7 @@ func Test_waitForJobResultsAsync_UsesConfiguredTimeout(t *testing.T) {
 	c := make(chan error)
 
 	go waitForJobResultsAsync(
+		context.Background(),
 		sf,
 		"1234",
 		ingestJobType,
@@ -879,13 +1005,203 @@ func Test_waitForJobResultsAsync_UsesConfiguredTimeout(t *testing.T) {
 	}
 }
 
-func Test_collectQueryResults(t *testing.T) {
-	csvData := `"col"` + "\n" + `"row"`
+func Test_waitForJobResults_RespectsContextCancellation(t *testing.T) {
+	jobResults := BulkJobResults{
+		Id:    "1234",
+		State: jobStateOpen,
+	}
+	server, sfAuth := setupTestServer(jobResults, http.StatusOK)
+	defer server.Close()
+
+	sf := buildSalesforceStruct(&sfAuth)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := waitForJobResults(ctx, sf, "1234", ingestJobType, time.Millisecond)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("waitForJobResults() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func Test_waitForJobResults_ReportsProgress(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		job := BulkJobResults{Id: "1234", State: jobStateUploadComplete, NumberRecordsProcessed: 5}
+		if polls > 1 {
+			job.State = jobStateJobComplete
+			job.NumberRecordsProcessed = 10
+			job.NumberRecordsFailed = 1
+		}
+		data, _ := json.Marshal(job)
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	sf := buildSalesforceStruct(&sfAuth)
+	var got []BulkJobProgress
+	sf.config.bulkProgress = func(progress BulkJobProgress) {
+		got = append(got, progress)
+	}
+
+	err := waitForJobResults(context.Background(), sf, "1234", ingestJobType, time.Millisecond)
+	if err != nil {
+		t.Fatalf("waitForJobResults() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("waitForJobResults() reported progress %d times, want 2", len(got))
+	}
+	if got[0].JobId != "1234" || got[0].JobType != BulkIngestJob ||
+		got[0].State != jobStateUploadComplete || got[0].PreviousState != "" ||
+		got[0].NumberRecordsProcessed != 5 {
+		t.Errorf("first progress = %+v", got[0])
+	}
+	if got[1].State != jobStateJobComplete || got[1].PreviousState != jobStateUploadComplete ||
+		got[1].NumberRecordsProcessed != 10 || got[1].NumberRecordsFailed != 1 ||
+		got[1].Elapsed < got[0].Elapsed {
+		t.Errorf("second progress = %+v", got[1])
+	}
+}
+
+func Test_pollUntilContextTimeout_Backoff(t *testing.T) {
+	var calls []time.Time
+	err := pollUntilContextTimeout(
+		context.Background(),
+		10*time.Millisecond,
+		20*time.Millisecond,
+		time.Second,
+		func(context.Context) (bool, error) {
+			calls = append(calls, time.Now())
+			return len(calls) == 4, nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("pollUntilContextTimeout() error = %v", err)
+	}
+	// the interval doubles from 10ms and is capped at 20ms
+	for i, want := range []time.Duration{20 * time.Millisecond, 20 * time.Millisecond} {
+		if gap := calls[i+2].Sub(calls[i+1]); gap < want {
+			t.Errorf("interval %d = %v, want at least %v", i+1, gap, want)
+		}
+	}
+	if total := calls[3].Sub(calls[0]); total >= 200*time.Millisecond {
+		t.Errorf("pollUntilContextTimeout() took %v, the interval is not capped", total)
+	}
+}
+
+func Test_newBulkIngestOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []BulkJobOption
+		want    csvFormat
+		wantErr bool
+	}{
+		{
+			name: "default",
+			want: csvFormat{},
+		},
+		{
+			name: "tab_crlf",
+			opts: []BulkJobOption{
+				WithColumnDelimiter(ColumnDelimiterTab),
+				WithLineEnding(LineEndingCRLF),
+			},
+			want: csvFormat{ColumnDelimiter: ColumnDelimiterTab, LineEnding: LineEndingCRLF},
+		},
+		{
+			name:    "invalid_delimiter",
+			opts:    []BulkJobOption{WithColumnDelimiter("|")},
+			wantErr: true,
+		},
+		{
+			name:    "empty_delimiter",
+			opts:    []BulkJobOption{WithColumnDelimiter("")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_line_ending",
+			opts:    []BulkJobOption{WithLineEnding("CR")},
+			wantErr: true,
+		},
+		{
+			name:    "query_all",
+			opts:    []BulkJobOption{WithQueryAll()},
+			wantErr: true,
+		},
+		{
+			name: "pk_chunking",
+			opts: []BulkJobOption{
+				WithColumnDelimiter(ColumnDelimiterTab),
+				WithPKChunking(1000, 2),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newBulkIngestOptions(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newBulkIngestOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("newBulkIngestOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_writeQueryResults_columnDelimiter(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.Contains(r.RequestURI, "?locator=") {
-			w.Header().Add("Sforce-Locator", "")
-		} else {
+		w.Header().Add("Sforce-Locator", "null")
+		if _, err := w.Write([]byte("col^text\r\nrow1^a,b\r\n")); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	options, err := newBulkQueryOptions("SELECT Id FROM Account", []BulkJobOption{
+		WithColumnDelimiter(ColumnDelimiterCaret),
+		WithLineEnding(LineEndingCRLF),
+	})
+	if err != nil {
+		t.Fatalf("newBulkQueryOptions() error = %v", err)
+	}
+	if options.request.ColumnDelimiter != ColumnDelimiterCaret ||
+		options.request.LineEnding != LineEndingCRLF {
+		t.Fatalf("newBulkQueryOptions() request = %+v", options.request)
+	}
+	var got strings.Builder
+	err = writeQueryResults(
+		context.Background(),
+		buildSalesforceStruct(&sfAuth),
+		[]*bulkQueryChunk{newCompletedQueryChunk("", "123")},
+		&got,
+		options,
+	)
+	if err != nil {
+		t.Fatalf("writeQueryResults() error = %v", err)
+	}
+	if want := "col^text\r\nrow1^a,b\r\n"; got.String() != want {
+		t.Errorf("writeQueryResults() = %q, want %q", got.String(), want)
+	}
+}
+
+func Test_writeQueryResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		csvData := `"col","text"` + "\n" + `"row1","a,b"` + "\n"
+		switch r.URL.Query().Get("locator") {
+		case "":
 			w.Header().Add("Sforce-Locator", "abc")
+		case "abc":
+			w.Header().Add("Sforce-Locator", "def")
+			csvData = `"col","text"` + "\n" + `"row2","multi` + "\n" + `line"` // no trailing newline
+		default:
+			w.Header().Add("Sforce-Locator", "null")
+			csvData = `"col","text"` + "\n"
 		}
 		w.Header().Add("Sforce-Numberofrecords", "1")
 		if _, err := w.Write([]byte(csvData)); err != nil {
@@ -901,43 +1217,40 @@ func Test_collectQueryResults(t *testing.T) {
 	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
 	defer badServer.Close()
 
-	type args struct {
-		sf        *Salesforce
-		bulkJobId string
-	}
 	tests := []struct {
 		name    string
-		args    args
-		want    [][]string
+		sf      *Salesforce
+		want    string
 		wantErr bool
 	}{
 		{
-			name: "query_with_locator",
-			args: args{
-				sf:        buildSalesforceStruct(&sfAuth),
-				bulkJobId: "123",
-			},
-			want:    [][]string{{"col"}, {"row"}, {"row"}},
+			name:    "query_with_locator",
+			sf:      buildSalesforceStruct(&sfAuth),
+			want:    "col,text\nrow1,\"a,b\"\nrow2,\"multi\nline\"\n",
 			wantErr: false,
 		},
 		{
-			name: "bad_request",
-			args: args{
-				sf:        buildSalesforceStruct(&badSfAuth),
-				bulkJobId: "123",
-			},
+			name:    "bad_request",
+			sf:      buildSalesforceStruct(&badSfAuth),
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := collectQueryResults(tt.args.sf, tt.args.bulkJobId)
+			var got strings.Builder
+			err := writeQueryResults(
+				context.Background(),
+				tt.sf,
+				[]*bulkQueryChunk{newCompletedQueryChunk("", "123")},
+				&got,
+				bulkJobOptions{},
+			)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("collectQueryResults() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("writeQueryResults() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("collectQueryResults() = %v, want %v", got, tt.want)
+			if got.String() != tt.want {
+				t.Errorf("writeQueryResults() = %q, want %q", got.String(), tt.want)
 			}
 		})
 	}
@@ -1025,7 +1338,7 @@ func Test_uploadJobData(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := uploadJobData(tt.args.sf, tt.args.data, tt.args.bulkJob); (err != nil) != tt.wantErr {
+			if err := uploadJobData(context.Background(), tt.args.sf, tt.args.data, tt.args.bulkJob); (err != nil) != tt.wantErr {
 				t.Errorf("uploadJobData() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -1147,7 +1460,7 @@ func Test_updateJobState(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := updateJobState(tt.args.job, tt.args.state, tt.args.sf); (err != nil) != tt.wantErr {
+			if err := updateJobState(context.Background(), tt.args.job, tt.args.state, tt.args.sf); (err != nil) != tt.wantErr {
 				t.Errorf("updateJobState() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -1308,6 +1621,7 @@ func Test_doBulkJobWithFile(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := doBulkJobWithFile(
+				context.Background(),
 				tt.args.sf,
 				tt.args.sObjectName,
 				tt.args.fieldName,
@@ -1316,6 +1630,7 @@ func Test_doBulkJobWithFile(t *testing.T) {
 				tt.args.batchSize,
 				tt.args.waitForResults,
 				"",
+				csvFormat{},
 			)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doBulkJobWithFile() error = %v, wantErr %v", err, tt.wantErr)
@@ -1405,13 +1720,153 @@ func Test_doQueryBulk(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := doQueryBulk(tt.args.sf, tt.args.filePath, tt.args.query); (err != nil) != tt.wantErr {
+			if err := doQueryBulk(context.Background(), tt.args.sf, tt.args.filePath, tt.args.query); (err != nil) != tt.wantErr {
 				t.Errorf("doQueryBulk() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func Test_newBulkQueryOptions(t *testing.T) {
+	tests := []struct {
+		name            string
+		opts            []BulkJobOption
+		wantMaxRecords  int
+		wantConcurrency int
+		wantErr         bool
+	}{
+		{
+			name:            "default",
+			wantConcurrency: 1,
+		},
+		{
+			name:            "max_records_and_concurrent_downloads",
+			opts:            []BulkJobOption{WithMaxRecords(50000), WithConcurrentDownloads(4)},
+			wantMaxRecords:  50000,
+			wantConcurrency: 4,
+		},
+		{
+			name:    "zero_max_records",
+			opts:    []BulkJobOption{WithMaxRecords(0)},
+			wantErr: true,
+		},
+		{
+			name:    "negative_max_records",
+			opts:    []BulkJobOption{WithMaxRecords(-1)},
+			wantErr: true,
+		},
+		{
+			name:    "zero_concurrent_downloads",
+			opts:    []BulkJobOption{WithConcurrentDownloads(0)},
+			wantErr: true,
+		},
+		{
+			name:    "negative_concurrent_downloads",
+			opts:    []BulkJobOption{WithConcurrentDownloads(-2)},
+			wantErr: true,
+		},
+		{
+			name:            "pk_chunking",
+			opts:            []BulkJobOption{WithPKChunking(100000, 4)},
+			wantConcurrency: 1,
+		},
+		{
+			name:    "zero_pk_chunk_size",
+			opts:    []BulkJobOption{WithPKChunking(0, 4)},
+			wantErr: true,
+		},
+		{
+			name:    "negative_pk_chunk_size",
+			opts:    []BulkJobOption{WithPKChunking(-100, 4)},
+			wantErr: true,
+		},
+		{
+			name:    "zero_pk_parallel_jobs",
+			opts:    []BulkJobOption{WithPKChunking(100000, 0)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newBulkQueryOptions("SELECT Id FROM Account", tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newBulkQueryOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr &&
+				(got.maxRecords != tt.wantMaxRecords || got.concurrency != tt.wantConcurrency) {
+				t.Errorf(
+					"newBulkQueryOptions() = %+v, want max records %d and concurrency %d",
+					got,
+					tt.wantMaxRecords,
+					tt.wantConcurrency,
+				)
+			}
+		})
+	}
+}
+
+func Test_createBulkQueryJob(t *testing.T) {
+	var gotRequest bulkQueryJobCreationRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gotRequest); err != nil {
+			t.Fatal(err.Error())
+		}
+		body, _ := json.Marshal(bulkJob{Id: "1234", State: jobStateOpen})
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+
+	tests := []struct {
+		name          string
+		opts          []BulkJobOption
+		wantOperation string
+	}{
+		{
+			name:          "query",
+			opts:          nil,
+			wantOperation: queryJobType,
+		},
+		{
+			name:          "query_all",
+			opts:          []BulkJobOption{WithQueryAll()},
+			wantOperation: queryAllOperation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := newBulkQueryOptions("", tt.opts)
+			if err != nil {
+				t.Fatalf("newBulkQueryOptions() error = %v", err)
+			}
+			job, err := createBulkQueryJob(
+				context.Background(),
+				buildSalesforceStruct(&sfAuth),
+				"SELECT Id FROM Account",
+				options,
+			)
+			if err != nil {
+				t.Fatalf("createBulkQueryJob() error = %v", err)
+			}
+			if job.Id != "1234" {
+				t.Errorf("createBulkQueryJob() job id = %v, want 1234", job.Id)
+			}
+			if gotRequest.Operation != tt.wantOperation {
+				t.Errorf(
+					"createBulkQueryJob() operation = %v, want %v",
+					gotRequest.Operation,
+					tt.wantOperation,
+				)
+			}
+		})
+	}
+}
+
 func Test_getJobRecordResults(t *testing.T) {
 	csvData := `"name"` + "\n" + `"test account"`
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1469,6 +1924,21 @@ func Test_getJobRecordResults(t *testing.T) {
 				SuccessfulRecords: []map[string]any{{
 					"name": "test account",
 				}},
+				UnprocessedRecords: []map[string]any{{
+					"name": "test account",
+				}},
+				Successful: []BulkRecordResult{{
+					Fields: map[string]string{"name": "test account"},
+					Index:  -1,
+				}},
+				Failed: []BulkRecordResult{{
+					Fields: map[string]string{"name": "test account"},
+					Index:  -1,
+				}},
+				Unprocessed: []BulkRecordResult{{
+					Fields: map[string]string{"name": "test account"},
+					Index:  -1,
+				}},
 			},
 			wantErr: false,
 		},
@@ -1492,13 +1962,21 @@ func Test_getJobRecordResults(t *testing.T) {
 				SuccessfulRecords: []map[string]any{{
 					"name": "test account",
 				}},
+				Successful: []BulkRecordResult{{
+					Fields: map[string]string{"name": "test account"},
+					Index:  -1,
+				}},
 			},
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getJobRecordResults(tt.args.sf, tt.args.bulkJobResults)
+			got, err := getJobRecordResults(
+				context.Background(),
+				tt.args.sf,
+				tt.args.bulkJobResults,
+			)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getJobRecordResults() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -1585,7 +2063,13 @@ func Test_getBulkJobRecords(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getBulkJobRecords(tt.args.sf, tt.args.bulkJobId, tt.args.resultType)
+			got, err := getBulkJobRecords(
+				context.Background(),
+				tt.args.sf,
+				tt.args.bulkJobId,
+				tt.args.resultType,
+				csvFormat{},
+			)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getBulkJobRecords() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -1647,3 +2131,534 @@ func Test_csvToMap(t *testing.T) {
 		})
 	}
 }
+
+func Test_indexBulkResults(t *testing.T) {
+	batch := []map[string]any{
+		{"FirstName": "Tony", "LastName": "Stark", "Age": 48},
+		{"FirstName": "Bruce", "LastName": nil, "Age": 49},
+		{"FirstName": "Tony", "LastName": "Stark", "Age": 48},
+		{"FirstName": "Steve", "LastName": "Rogers", "Age": 105},
+	}
+	row := func(id string, firstName string, lastName string, age string) BulkRecordResult {
+		return BulkRecordResult{
+			Id:     id,
+			Fields: map[string]string{"FirstName": firstName, "LastName": lastName, "Age": age},
+			Index:  -1,
+		}
+	}
+	jobResults := BulkJobResults{
+		Successful: []BulkRecordResult{
+			row("003B", "Tony", "Stark", "48"),
+			row("003A", "Tony", "Stark", "48"),
+		},
+		Failed: []BulkRecordResult{row("", "Bruce", "", "49")},
+		Unprocessed: []BulkRecordResult{
+			row("", "Steve", "Rogers", "105"),
+			row("", "Peter", "Parker", "16"),
+		},
+	}
+	indexBulkResults(batch, 10, &jobResults)
+
+	got := []int{
+		jobResults.Successful[0].Index,
+		jobResults.Successful[1].Index,
+		jobResults.Failed[0].Index,
+		jobResults.Unprocessed[0].Index,
+		jobResults.Unprocessed[1].Index,
+	}
+	if want := []int{10, 12, 11, 13, -1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("indexBulkResults() indexes = %v, want %v", got, want)
+	}
+}
+
+func TestMatchBulkResults(t *testing.T) {
+	type contact struct {
+		LastName string
+	}
+	records := []contact{{LastName: "Stark"}, {LastName: "Banner"}, {LastName: "Rogers"}}
+	results := []BulkJobResults{
+		{
+			Successful: []BulkRecordResult{
+				{Fields: map[string]string{"LastName": "Banner"}, Index: -1},
+			},
+			Failed: []BulkRecordResult{
+				{Fields: map[string]string{"LastName": "Stark"}, Index: -1},
+			},
+		},
+		{
+			Successful: []BulkRecordResult{
+				{Fields: map[string]string{"LastName": "Rogers"}, Index: -1},
+			},
+		},
+	}
+	if err := MatchBulkResults(records, 2, results); err != nil {
+		t.Fatalf("MatchBulkResults() error = %v", err)
+	}
+	got := []int{
+		results[0].Successful[0].Index,
+		results[0].Failed[0].Index,
+		results[1].Successful[0].Index,
+	}
+	if want := []int{1, 0, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MatchBulkResults() indexes = %v, want %v", got, want)
+	}
+
+	if err := MatchBulkResults(records, 0, results); err == nil {
+		t.Error("MatchBulkResults() expected error for batch size 0")
+	}
+	if err := MatchBulkResults(contact{}, 2, results); err == nil {
+		t.Error("MatchBulkResults() expected error for records that are not a slice")
+	}
+}
+
+func Test_newBulkRecordResults(t *testing.T) {
+	records := []map[string]any{
+		{"sf__Id": "003A", "sf__Created": "true", "LastName": "Stark"},
+		{
+			"sf__Id":    "",
+			"sf__Error": "REQUIRED_FIELD_MISSING:Required fields are missing",
+			"LastName":  "",
+		},
+	}
+	want := []BulkRecordResult{
+		{Id: "003A", Created: true, Fields: map[string]string{"LastName": "Stark"}, Index: -1},
+		{
+			Error:  "REQUIRED_FIELD_MISSING:Required fields are missing",
+			Fields: map[string]string{"LastName": ""},
+			Index:  -1,
+		},
+	}
+	if got := newBulkRecordResults(records); !reflect.DeepEqual(got, want) {
+		t.Errorf("newBulkRecordResults() = %v, want %v", got, want)
+	}
+	if got := newBulkRecordResults(nil); got != nil {
+		t.Errorf("newBulkRecordResults() = %v, want nil", got)
+	}
+}
+
+func Test_parseBulkRecordError(t *testing.T) {
+	tests := []struct {
+		name    string
+		sfError string
+		want    SalesforceErrorMessage
+	}{
+		{
+			name:    "status_code",
+			sfError: "DUPLICATE_VALUE:duplicate value found: Email__c",
+			want: SalesforceErrorMessage{
+				StatusCode: "DUPLICATE_VALUE",
+				Message:    "duplicate value found: Email__c",
+			},
+		},
+		{
+			name:    "message_only",
+			sfError: "unknown error",
+			want:    SalesforceErrorMessage{Message: "unknown error"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseBulkRecordError(tt.sfError); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseBulkRecordError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// setupBulkJobsServer fakes ingest jobs that complete, except job2 which fails
+func setupBulkJobsServer(t *testing.T) (*httptest.Server, authentication) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/services/data/"+apiVersion+"/jobs/ingest/")
+		id, resultType, _ := strings.Cut(path, "/")
+		if resultType == unprocessedResults {
+			if _, err := w.Write([]byte("Name\nunprocessed\n")); err != nil {
+				t.Fatal(err.Error())
+			}
+			return
+		}
+		if resultType != "" {
+			if _, err := w.Write([]byte("sf__Id,sf__Created,Name\n" + id + "-1,true,test\n")); err != nil {
+				t.Fatal(err.Error())
+			}
+			return
+		}
+		job := BulkJobResults{Id: id, State: jobStateJobComplete}
+		switch id {
+		case "job2":
+			job.State = jobStateFailed
+			job.ErrorMessage = "InvalidBatch"
+		case "job4":
+			job.State = jobStateOpen
+		case "job5":
+			job.State = jobStateAborted
+		}
+		body, _ := json.Marshal(job)
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+	return server, sfAuth
+}
+
+func Test_waitForBulkJobs(t *testing.T) {
+	server, sfAuth := setupBulkJobsServer(t)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	t.Run("all_jobs_complete", func(t *testing.T) {
+		err := waitForBulkJobs(context.Background(), sf, []string{"job1", "job3"}, ingestJobType)
+		if err != nil {
+			t.Errorf("waitForBulkJobs() error = %v", err)
+		}
+	})
+
+	t.Run("failed_job_after_first", func(t *testing.T) {
+		err := waitForBulkJobs(
+			context.Background(),
+			sf,
+			[]string{"job1", "job2", "job3"},
+			ingestJobType,
+		)
+		if err == nil || !strings.Contains(err.Error(), "bulk job job2") {
+			t.Fatalf("waitForBulkJobs() error = %v, want error for job2", err)
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Errors[0].Message != "InvalidBatch" {
+			t.Errorf("waitForBulkJobs() error = %v, want APIError with job error message", err)
+		}
+	})
+}
+
+func Test_csvBatcher(t *testing.T) {
+	t.Run("split_by_rows_and_bytes", func(t *testing.T) {
+		batcher, err := newCSVBatcher(
+			strings.NewReader("Name\na\nbb\nccc\n\"d,d\"\n"),
+			csvFormat{},
+		)
+		if err != nil {
+			t.Fatalf("newCSVBatcher() error = %v", err)
+		}
+		var batches []string
+		for batcher.next() {
+			var buf strings.Builder
+			if err := batcher.writeBatch(&buf, 2, 12); err != nil {
+				t.Fatalf("csvBatcher.writeBatch() error = %v", err)
+			}
+			batches = append(batches, buf.String())
+		}
+		want := []string{"Name\na\nbb\n", "Name\nccc\n", "Name\n\"d,d\"\n"}
+		if !reflect.DeepEqual(batches, want) {
+			t.Errorf("csvBatcher batches = %q, want %q", batches, want)
+		}
+	})
+
+	t.Run("row_exceeds_limit", func(t *testing.T) {
+		batcher, err := newCSVBatcher(
+			strings.NewReader("Name\n"+strings.Repeat("a", 20)+"\n"),
+			csvFormat{},
+		)
+		if err != nil {
+			t.Fatalf("newCSVBatcher() error = %v", err)
+		}
+		if !batcher.next() {
+			t.Fatal("csvBatcher.next() = false, want true")
+		}
+		if err := batcher.writeBatch(io.Discard, 10, 12); err == nil {
+			t.Error("csvBatcher.writeBatch() expected error")
+		}
+	})
+
+	t.Run("no_header", func(t *testing.T) {
+		if _, err := newCSVBatcher(strings.NewReader(""), csvFormat{}); err == nil {
+			t.Error("newCSVBatcher() expected error")
+		}
+	})
+
+	t.Run("invalid_csv", func(t *testing.T) {
+		batcher, err := newCSVBatcher(strings.NewReader("Id,Name\n1,a\n2\n"), csvFormat{})
+		if err != nil {
+			t.Fatalf("newCSVBatcher() error = %v", err)
+		}
+		for batcher.next() {
+			if err := batcher.writeBatch(io.Discard, 10, 100); err != nil {
+				return
+			}
+		}
+		if batcher.err == nil {
+			t.Error("csvBatcher expected error for row with wrong number of fields")
+		}
+	})
+}
+
+func Test_doBulkJobWithReader(t *testing.T) {
+	var uploads []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/batches") {
+			body, _ := io.ReadAll(r.Body)
+			uploads = append(uploads, string(body))
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		body, _ := json.Marshal(
+			bulkJob{Id: "job" + strconv.Itoa(len(uploads)), State: jobStateOpen},
+		)
+		if r.Method == http.MethodGet {
+			body, _ = json.Marshal(BulkJobResults{Id: "job", State: jobStateJobComplete})
+		}
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	uploadFailServer, uploadFailSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer uploadFailServer.Close()
+
+	defaultUploadSizeMax := bulkUploadSizeMax
+	bulkUploadSizeMax = 20
+	defer func() { bulkUploadSizeMax = defaultUploadSizeMax }()
+
+	t.Run("split_into_jobs", func(t *testing.T) {
+		uploads = nil
+		jobIds, err := doBulkJobWithReader(
+			context.Background(),
+			buildSalesforceStruct(&sfAuth),
+			"Account",
+			"",
+			insertOperation,
+			strings.NewReader("Name\nrow1\nrow2\nrow3\nrow-number-4\n"),
+			2,
+			true,
+			"",
+			csvFormat{},
+		)
+		if err != nil {
+			t.Fatalf("doBulkJobWithReader() error = %v", err)
+		}
+		want := []string{"Name\nrow1\nrow2\n", "Name\nrow3\n", "Name\nrow-number-4\n"}
+		if !reflect.DeepEqual(uploads, want) {
+			t.Errorf("doBulkJobWithReader() uploaded %q, want %q", uploads, want)
+		}
+		if !reflect.DeepEqual(jobIds, []string{"job0", "job1", "job2"}) {
+			t.Errorf("doBulkJobWithReader() job ids = %v", jobIds)
+		}
+	})
+
+	t.Run("row_too_large", func(t *testing.T) {
+		uploads = nil
+		jobIds, err := doBulkJobWithReader(
+			context.Background(),
+			buildSalesforceStruct(&sfAuth),
+			"Account",
+			"",
+			insertOperation,
+			strings.NewReader("Name\nrow1\nthis-row-is-too-large\n"),
+			2,
+			false,
+			"",
+			csvFormat{},
+		)
+		if err == nil || !strings.Contains(err.Error(), "exceeds the bulk upload limit") {
+			t.Errorf("doBulkJobWithReader() error = %v, want row size error", err)
+		}
+		if len(jobIds) != 2 {
+			t.Errorf("doBulkJobWithReader() job ids = %v, want the ids of both jobs", jobIds)
+		}
+	})
+
+	t.Run("create_job_fail", func(t *testing.T) {
+		_, err := doBulkJobWithReader(
+			context.Background(),
+			buildSalesforceStruct(&uploadFailSfAuth),
+			"Account",
+			"",
+			insertOperation,
+			strings.NewReader("Name\nrow1\n"),
+			2,
+			false,
+			"",
+			csvFormat{},
+		)
+		if err == nil {
+			t.Error("doBulkJobWithReader() expected error")
+		}
+	})
+
+	t.Run("no_header", func(t *testing.T) {
+		_, err := doBulkJobWithReader(
+			context.Background(),
+			buildSalesforceStruct(&sfAuth),
+			"Account",
+			"",
+			insertOperation,
+			strings.NewReader(""),
+			2,
+			false,
+			"",
+			csvFormat{},
+		)
+		if err == nil {
+			t.Error("doBulkJobWithReader() expected error")
+		}
+	})
+}
+
+func Test_doListBulkJobs(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		list := bulkJobList{Done: true, Records: []BulkJobInfo{{Id: "job2"}}}
+		if r.URL.Query().Get("queryLocator") == "" {
+			list = bulkJobList{
+				Done:           false,
+				Records:        []BulkJobInfo{{Id: "job1", JobType: "V2Query"}},
+				NextRecordsUrl: r.URL.Path + "?queryLocator=01g",
+			}
+		}
+		body, _ := json.Marshal(list)
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	t.Run("follow_next_records_url", func(t *testing.T) {
+		queries = nil
+		enabled := false
+		jobs, err := doListBulkJobs(
+			context.Background(),
+			buildSalesforceStruct(&sfAuth),
+			BulkJobFilter{
+				JobType:             BulkQueryJob,
+				APIJobType:          "V2Query",
+				IsPkChunkingEnabled: &enabled,
+			},
+		)
+		if err != nil {
+			t.Fatalf("doListBulkJobs() error = %v", err)
+		}
+		if len(jobs) != 2 || jobs[0].Id != "job1" || jobs[1].Id != "job2" {
+			t.Errorf("doListBulkJobs() = %v, want job1 and job2", jobs)
+		}
+		wantQueries := []string{"isPkChunkingEnabled=false&jobType=V2Query", "queryLocator=01g"}
+		if !reflect.DeepEqual(queries, wantQueries) {
+			t.Errorf("doListBulkJobs() queries = %v, want %v", queries, wantQueries)
+		}
+	})
+
+	t.Run("invalid_job_type", func(t *testing.T) {
+		_, err := doListBulkJobs(
+			context.Background(),
+			buildSalesforceStruct(&sfAuth),
+			BulkJobFilter{JobType: "batch"},
+		)
+		if err == nil {
+			t.Error("doListBulkJobs() expected error")
+		}
+	})
+
+	t.Run("bad_request", func(t *testing.T) {
+		_, err := doListBulkJobs(
+			context.Background(),
+			buildSalesforceStruct(&badSfAuth),
+			BulkJobFilter{},
+		)
+		if err == nil {
+			t.Error("doListBulkJobs() expected error")
+		}
+	})
+}
+
+func Test_doBulkJobRequest(t *testing.T) {
+	var gotMethod, gotPath, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if _, err := w.Write([]byte(`{"id":"750","state":"Aborted","apiVersion":63.0}`)); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	tests := []struct {
+		name     string
+		method   string
+		jobType  BulkJobType
+		jobId    string
+		body     string
+		want     BulkJobInfo
+		wantPath string
+		wantErr  bool
+	}{
+		{
+			name:     "abort_query_job",
+			method:   http.MethodPatch,
+			jobType:  BulkQueryJob,
+			jobId:    "750",
+			body:     `{"state":"Aborted"}`,
+			want:     BulkJobInfo{Id: "750", State: jobStateAborted, ApiVersion: 63.0},
+			wantPath: "/services/data/" + apiVersion + "/jobs/query/750",
+		},
+		{
+			name:     "delete_ingest_job",
+			method:   http.MethodDelete,
+			jobType:  BulkIngestJob,
+			jobId:    "750",
+			want:     BulkJobInfo{},
+			wantPath: "/services/data/" + apiVersion + "/jobs/ingest/750",
+		},
+		{
+			name:    "invalid_job_type",
+			method:  http.MethodGet,
+			jobType: "",
+			jobId:   "750",
+			wantErr: true,
+		},
+		{
+			name:    "missing_id",
+			method:  http.MethodGet,
+			jobType: BulkIngestJob,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPath = ""
+			got, err := doBulkJobRequest(
+				context.Background(),
+				buildSalesforceStruct(&sfAuth),
+				tt.method,
+				tt.jobType,
+				tt.jobId,
+				tt.body,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("doBulkJobRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("doBulkJobRequest() = %v, want %v", got, tt.want)
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotMethod != tt.method || gotPath != tt.wantPath || gotBody != tt.body {
+				t.Errorf(
+					"doBulkJobRequest() sent %s %s %s, want %s %s %s",
+					gotMethod, gotPath, gotBody, tt.method, tt.wantPath, tt.body,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/florezzep/go-salesforce"
+)
+
+// goTypes maps Salesforce field types to Go types that mapstructure can decode query results into.
+// Dates and times are kept as strings, as returned by the REST API.
+var goTypes = map[string]string{
+	"id":              "string",
+	"reference":       "string",
+	"string":          "string",
+	"textarea":        "string",
+	"picklist":        "string",
+	"multipicklist":   "string",
+	"combobox":        "string",
+	"phone":           "string",
+	"email":           "string",
+	"url":             "string",
+	"encryptedstring": "string",
+	"base64":          "string",
+	"date":            "string",
+	"datetime":        "string",
+	"time":            "string",
+	"boolean":         "bool",
+	"int":             "int",
+	"long":            "int64",
+	"double":          "float64",
+	"currency":        "float64",
+	"percent":         "float64",
+	"address":         "map[string]any",
+	"location":        "map[string]any",
+	"anyType":         "any",
+	"complexvalue":    "any",
+}
+
+// compoundTypes cannot be selected with go-soql or written, so they only get a salesforce tag
+var compoundTypes = []string{"address", "location", "complexvalue"}
+
+// generate renders the Go source for the given objects.
+func generate(pkg string, describes []salesforce.SObjectDescribe) ([]byte, error) {
+	describes = slices.Clone(describes)
+	slices.SortFunc(describes, func(a, b salesforce.SObjectDescribe) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	structNames := map[string]string{}
+	for _, describe := range describes {
+		structNames[describe.Name] = goIdentifier(describe.Name)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by sfgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n", pkg)
+	for _, describe := range describes {
+		writeStruct(&buf, describe, structNames)
+		writePicklistConstants(&buf, describe, structNames[describe.Name])
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return source, nil
+}
+
+func writeStruct(
+	buf *bytes.Buffer,
+	describe salesforce.SObjectDescribe,
+	structNames map[string]string,
+) {
+	structName := structNames[describe.Name]
+	fieldNames := map[string]bool{}
+	uniqueName := func(name string) string {
+		unique := name
+		for i := 2; fieldNames[unique]; i++ {
+			unique = name + strconv.Itoa(i)
+		}
+		fieldNames[unique] = true
+		return unique
+	}
+
+	fmt.Fprintf(buf, "\n// %s is the %s (%s) object.\n", structName, describe.Label, describe.Name)
+	fmt.Fprintf(buf, "type %s struct {\n", structName)
+	for _, field := range describe.Fields {
+		goType, ok := goTypes[field.Type]
+		if !ok {
+			goType = "any"
+		}
+		tags := fmt.Sprintf(`salesforce:"%s,omitempty%s"`, field.Name, dmlTagOption(field))
+		if !slices.Contains(compoundTypes, field.Type) {
+			tags += fmt.Sprintf(` soql:"selectColumn,fieldName=%s"`, field.Name)
+		}
+		fmt.Fprintf(buf, "\t%s %s `%s`", uniqueName(goIdentifier(field.Name)), goType, tags)
+		if field.Label != "" {
+			fmt.Fprintf(buf, " // %s", field.Label)
+		}
+		buf.WriteString("\n")
+	}
+
+	// parent relationships decode the related record when it is selected, e.g. Owner.Name,
+	// and are readonly because Salesforce rejects the nested record in DML
+	for _, field := range describe.Fields {
+		if field.RelationshipName == "" {
+			continue
+		}
+		relatedType := "map[string]any"
+		if len(field.ReferenceTo) == 1 {
+			if related, ok := structNames[field.ReferenceTo[0]]; ok {
+				relatedType = "*" + related
+			}
+		}
+		fmt.Fprintf(
+			buf,
+			"\t%s %s `salesforce:\"%s,omitempty,readonly\"`\n",
+			uniqueName(goIdentifier(field.RelationshipName)),
+			relatedType,
+			field.RelationshipName,
+		)
+	}
+	buf.WriteString("}\n")
+
+	fmt.Fprintf(buf, "\n// SObjectName returns the API name of the object.\n")
+	fmt.Fprintf(buf, "func (%s) SObjectName() string { return %q }\n", structName, describe.Name)
+}
+
+// dmlTagOption returns the salesforce tag option that keeps fields Salesforce does not accept out of DML:
+// readonly for fields that can be neither created nor updated and createonly for fields that cannot be updated.
+// Id is left out as it identifies the record to update, upsert or delete.
+func dmlTagOption(field salesforce.SObjectField) string {
+	switch {
+	case field.Name == "Id" || field.Updateable:
+		return ""
+	case field.Createable:
+		return ",createonly"
+	default:
+		return ",readonly"
+	}
+}
+
+func writePicklistConstants(
+	buf *bytes.Buffer,
+	describe salesforce.SObjectDescribe,
+	structName string,
+) {
+	constNames := map[string]bool{}
+	var lines []string
+	for _, field := range describe.Fields {
+		if field.Type != "picklist" && field.Type != "multipicklist" {
+			continue
+		}
+		prefix := structName + goIdentifier(field.Name)
+		for _, value := range field.PicklistValues {
+			if !value.Active {
+				continue
+			}
+			name := prefix + goIdentifier(value.Value)
+			unique := name
+			for i := 2; constNames[unique]; i++ {
+				unique = name + strconv.Itoa(i)
+			}
+			constNames[unique] = true
+			lines = append(lines, fmt.Sprintf("\t%s = %q\n", unique, value.Value))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(buf, "\n// Picklist values of %s.\nconst (\n", describe.Name)
+	for _, line := range lines {
+		buf.WriteString(line)
+	}
+	buf.WriteString(")\n")
+}
+
+// goIdentifier converts a Salesforce API name into an exported Go identifier,
+// e.g. Account_Number__c becomes AccountNumber and ns__Invoice__mdt becomes NsInvoiceMdt.
+func goIdentifier(name string) string {
+	name = strings.TrimSuffix(name, "__c")
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	identifier := b.String()
+	if identifier == "" || !unicode.IsUpper([]rune(identifier)[0]) {
+		identifier = "X" + identifier
+	}
+	return identifier
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/florezzep/go-salesforce"
+)
+
+var testDescribes = []salesforce.SObjectDescribe{
+	{
+		Name:  "Invoice__c",
+		Label: "Invoice",
+		Fields: []salesforce.SObjectField{
+			{Name: "Id", Label: "Record ID", Type: "id"},
+			{
+				Name:       "Amount__c",
+				Label:      "Amount",
+				Type:       "currency",
+				Createable: true,
+				Updateable: true,
+			},
+			{Name: "Paid__c", Label: "Paid", Type: "boolean", Createable: true, Updateable: true},
+			{Name: "Lines__c", Label: "Lines", Type: "int"},
+			{Name: "Billing_Address__c", Label: "Billing Address", Type: "address"},
+			{
+				Name:             "Account__c",
+				Label:            "Account",
+				Type:             "reference",
+				Createable:       true,
+				RelationshipName: "Account__r",
+				ReferenceTo:      []string{"Account"},
+			},
+			{
+				Name:             "OwnerId",
+				Label:            "Owner ID",
+				Type:             "reference",
+				Createable:       true,
+				Updateable:       true,
+				RelationshipName: "Owner",
+				ReferenceTo:      []string{"Group", "User"},
+			},
+			{
+				Name:       "Status__c",
+				Label:      "Status",
+				Type:       "picklist",
+				Createable: true,
+				Updateable: true,
+				PicklistValues: []salesforce.PicklistValue{
+					{Value: "Draft", Active: true},
+					{Value: "Sent to customer", Active: true},
+					{Value: "Old", Active: false},
+				},
+			},
+		},
+	},
+	{
+		Name:  "Account",
+		Label: "Account",
+		Fields: []salesforce.SObjectField{
+			{Name: "Id", Label: "Account ID", Type: "id"},
+			{
+				Name:       "Name",
+				Label:      "Account Name",
+				Type:       "string",
+				Createable: true,
+				Updateable: true,
+			},
+		},
+	},
+}
+
+func TestGenerate(t *testing.T) {
+	source, err := generate("models", testDescribes)
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "models.go", source, 0); err != nil {
+		t.Fatalf("generate() produced invalid Go: %v\n%s", err, source)
+	}
+
+	code := strings.Join(strings.Fields(string(source)), " ") // ignore gofmt alignment
+	if strings.Index(code, "type Account struct") > strings.Index(code, "type Invoice struct") {
+		t.Error("generate() did not sort objects by name")
+	}
+	wants := []string{
+		"// Code generated by sfgen. DO NOT EDIT.",
+		"package models",
+		"Amount float64 `salesforce:\"Amount__c,omitempty\" soql:\"selectColumn,fieldName=Amount__c\"` // Amount",
+		"Paid bool `salesforce:\"Paid__c,omitempty\" soql:\"selectColumn,fieldName=Paid__c\"`",
+		"Id string `salesforce:\"Id,omitempty\"",
+		"Lines int `salesforce:\"Lines__c,omitempty,readonly\"",
+		"BillingAddress map[string]any `salesforce:\"Billing_Address__c,omitempty,readonly\"` // Billing Address",
+		"Account string `salesforce:\"Account__c,omitempty,createonly\"",
+		"OwnerId string `salesforce:\"OwnerId,omitempty\"",
+		"AccountR *Account `salesforce:\"Account__r,omitempty,readonly\"`",
+		"Owner map[string]any `salesforce:\"Owner,omitempty,readonly\"`",
+		"func (Invoice) SObjectName() string { return \"Invoice__c\" }",
+		"InvoiceStatusDraft = \"Draft\"",
+		"InvoiceStatusSentToCustomer = \"Sent to customer\"",
+	}
+	for _, want := range wants {
+		if !strings.Contains(code, want) {
+			t.Errorf("generate() missing %q in:\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "InvoiceStatusOld") {
+		t.Error("generate() included an inactive picklist value")
+	}
+}
+
+func Test_goIdentifier(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Id", want: "Id"},
+		{name: "Account_Number__c", want: "AccountNumber"},
+		{name: "ns__Invoice__mdt", want: "NsInvoiceMdt"},
+		{name: "Owner", want: "Owner"},
+		{name: "2nd_Contact__c", want: "X2ndContact"},
+		{name: "closed won", want: "ClosedWon"},
+		{name: "---", want: "X"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := goIdentifier(tt.name); got != tt.want {
+				t.Errorf("goIdentifier() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_run_describeFile(t *testing.T) {
+	dir := t.TempDir()
+	describePath := filepath.Join(dir, "describe.json")
+	savedPath := filepath.Join(dir, "saved.json")
+	outPath := filepath.Join(dir, "models.go")
+	single := `{"name":"Account","label":"Account","fields":[{"name":"Id","type":"id"}]}`
+	if err := os.WriteFile(describePath, []byte(single), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := run([]string{
+		"-describe", describePath,
+		"-save-describe", savedPath,
+		"-package", "crm",
+		"-out", outPath,
+	})
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	out, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "package crm") ||
+		!strings.Contains(string(out), "type Account struct") {
+		t.Errorf("run() wrote unexpected code:\n%s", out)
+	}
+
+	// the saved describe is a list that can be read back
+	if err := run([]string{"-describe", savedPath, "-objects", "Account", "-out", outPath}); err != nil {
+		t.Errorf("run() with saved describe error = %v", err)
+	}
+	if err := run([]string{"-describe", savedPath, "-objects", "Contact"}); err == nil {
+		t.Error("run() expected error for object missing from describe file")
+	}
+}
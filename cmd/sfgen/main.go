@@ -0,0 +1,181 @@
+// Command sfgen generates Go structs from the metadata of Salesforce objects.
+//
+// The structs have salesforce tags for Query, InsertCollection, InsertBulk and the other DML functions,
+// soql tags for QueryStruct, and constants for the active picklist values.
+//
+// Usage:
+//
+//	sfgen -objects Account,Contact -package models -out models/salesforce.go
+//	sfgen -describe describe.json -package models
+//
+// Credentials are read from environment variables: SF_DOMAIN, SF_CONSUMER_KEY, SF_CONSUMER_SECRET,
+// SF_USERNAME, SF_PASSWORD, SF_SECURITY_TOKEN, SF_ACCESS_TOKEN, SF_REFRESH_TOKEN and SF_RSA_PEM_FILE.
+// The describe results can be saved with -save-describe and used later with -describe to generate
+// code without connecting to the org.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/florezzep/go-salesforce"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "sfgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("sfgen", flag.ContinueOnError)
+	objects := flags.String("objects", "", "comma separated API names of the objects to generate")
+	pkg := flags.String("package", "models", "package name of the generated file")
+	out := flags.String("out", "", "file to write, standard output if empty")
+	describeFile := flags.String(
+		"describe",
+		"",
+		"read describe results from a JSON file instead of the org",
+	)
+	saveDescribe := flags.String("save-describe", "", "write the describe results to a JSON file")
+	apiVersion := flags.String("api-version", "", "API version to use, e.g. v63.0")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var describes []salesforce.SObjectDescribe
+	var err error
+	if *describeFile != "" {
+		describes, err = readDescribes(*describeFile, splitObjects(*objects))
+	} else {
+		describes, err = fetchDescribes(splitObjects(*objects), *apiVersion)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *saveDescribe != "" {
+		data, err := json.MarshalIndent(describes, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*saveDescribe, data, 0o644); err != nil {
+			return err
+		}
+	}
+
+	source, err := generate(*pkg, describes)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(source)
+		return err
+	}
+	return os.WriteFile(*out, source, 0o644)
+}
+
+func splitObjects(objects string) []string {
+	var names []string
+	for _, name := range strings.Split(objects, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// readDescribes reads a JSON file containing one describe result or a list of them,
+// keeping only the given objects when any are given.
+func readDescribes(path string, objects []string) ([]salesforce.SObjectDescribe, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var describes []salesforce.SObjectDescribe
+	if err := json.Unmarshal(data, &describes); err != nil {
+		var describe salesforce.SObjectDescribe
+		if singleErr := json.Unmarshal(data, &describe); singleErr != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		describes = []salesforce.SObjectDescribe{describe}
+	}
+	if len(objects) == 0 {
+		return describes, nil
+	}
+
+	var selected []salesforce.SObjectDescribe
+	for _, name := range objects {
+		found := false
+		for _, describe := range describes {
+			if strings.EqualFold(describe.Name, name) {
+				selected = append(selected, describe)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("object %s not found in %s", name, path)
+		}
+	}
+	return selected, nil
+}
+
+func fetchDescribes(objects []string, apiVersion string) ([]salesforce.SObjectDescribe, error) {
+	if len(objects) == 0 {
+		return nil, errors.New("-objects is required when not using -describe")
+	}
+	creds, err := credsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	var options []salesforce.Option
+	if apiVersion != "" {
+		options = append(options, salesforce.WithAPIVersion(apiVersion))
+	}
+	sf, err := salesforce.Init(creds, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	describes := make([]salesforce.SObjectDescribe, 0, len(objects))
+	for _, name := range objects {
+		describe, err := sf.DescribeSObject(name)
+		if err != nil {
+			return nil, fmt.Errorf("describing %s: %w", name, err)
+		}
+		describes = append(describes, describe)
+	}
+	return describes, nil
+}
+
+func credsFromEnv() (salesforce.Creds, error) {
+	creds := salesforce.Creds{
+		Domain:         os.Getenv("SF_DOMAIN"),
+		Username:       os.Getenv("SF_USERNAME"),
+		Password:       os.Getenv("SF_PASSWORD"),
+		SecurityToken:  os.Getenv("SF_SECURITY_TOKEN"),
+		ConsumerKey:    os.Getenv("SF_CONSUMER_KEY"),
+		ConsumerSecret: os.Getenv("SF_CONSUMER_SECRET"),
+		AccessToken:    os.Getenv("SF_ACCESS_TOKEN"),
+		RefreshToken:   os.Getenv("SF_REFRESH_TOKEN"),
+	}
+	if pemFile := os.Getenv("SF_RSA_PEM_FILE"); pemFile != "" {
+		pem, err := os.ReadFile(pemFile)
+		if err != nil {
+			return salesforce.Creds{}, err
+		}
+		creds.ConsumerRSAPem = string(pem)
+	}
+	if creds == (salesforce.Creds{}) {
+		return salesforce.Creds{}, errors.New(
+			"no credentials found, set the SF_* environment variables",
+		)
+	}
+	return creds, nil
+}
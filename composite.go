@@ -1,6 +1,7 @@
 package salesforce
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -33,12 +34,16 @@ type compositeSubRequestResult struct {
 	ReferenceId    string             `json:"referenceId"`
 }
 
-func doCompositeRequest(sf *Salesforce, compReq compositeRequest) (SalesforceResults, error) {
+func doCompositeRequest(
+	ctx context.Context,
+	sf *Salesforce,
+	compReq compositeRequest,
+) (SalesforceResults, error) {
 	body, jsonErr := json.Marshal(compReq)
 	if jsonErr != nil {
 		return SalesforceResults{}, jsonErr
 	}
-	resp, httpErr := doRequest(sf.auth, sf.config, requestPayload{
+	resp, httpErr := doRequest(ctx, sf.auth, sf.config, requestPayload{
 		method:   http.MethodPost,
 		uri:      "/composite",
 		content:  jsonType,
@@ -124,7 +129,24 @@ func processCompositeResponse(resp http.Response, allOrNone bool) (SalesforceRes
 		return SalesforceResults{}, jsonError
 	}
 
-	for _, subResult := range compositeResults.CompositeResponse {
+	// a subrequest that failed as a whole, e.g. PROCESSING_HALTED when allOrNone is set, has a body of
+	// error messages rather than record results. Decode those as well so the error details are not lost.
+	subRequestErrors := struct {
+		CompositeResponse []struct {
+			Body []SalesforceErrorMessage `json:"body"`
+		} `json:"compositeResponse"`
+	}{}
+	_ = json.Unmarshal(responseData, &subRequestErrors)
+
+	for i, subResult := range compositeResults.CompositeResponse {
+		if subResult.HttpStatusCode >= 300 && i < len(subRequestErrors.CompositeResponse) {
+			for j, sfError := range subRequestErrors.CompositeResponse[i].Body {
+				if sfError.ErrorCode != "" && j < len(subResult.Body) &&
+					len(subResult.Body[j].Errors) == 0 {
+					subResult.Body[j].Errors = []SalesforceErrorMessage{sfError}
+				}
+			}
+		}
 		for _, result := range subResult.Body {
 			if !result.Success {
 				results.HasSalesforceErrors = true
@@ -137,13 +159,14 @@ func processCompositeResponse(resp http.Response, allOrNone bool) (SalesforceRes
 }
 
 func doInsertComposite(
+	ctx context.Context,
 	sf *Salesforce,
 	sObjectName string,
 	records any,
 	allOrNone bool,
 	batchSize int,
 ) (SalesforceResults, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+	recordMap, err := convertToSliceOfMaps(records, insertOperation)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
@@ -153,7 +176,7 @@ func doInsertComposite(
 		recordMap[i]["attributes"] = map[string]string{"type": sObjectName}
 	}
 
-	uri := "/services/data/" + apiVersion + "/composite/sobjects"
+	uri := sf.config.dataPath() + "/composite/sobjects"
 	compReq, compositeErr := createCompositeRequestForCollection(
 		http.MethodPost,
 		uri,
@@ -164,7 +187,7 @@ func doInsertComposite(
 	if compositeErr != nil {
 		return SalesforceResults{}, compositeErr
 	}
-	results, compositeReqErr := doCompositeRequest(sf, compReq)
+	results, compositeReqErr := doCompositeRequest(ctx, sf, compReq)
 	if compositeReqErr != nil {
 		return SalesforceResults{}, compositeReqErr
 	}
@@ -173,13 +196,14 @@ func doInsertComposite(
 }
 
 func doUpdateComposite(
+	ctx context.Context,
 	sf *Salesforce,
 	sObjectName string,
 	records any,
 	allOrNone bool,
 	batchSize int,
 ) (SalesforceResults, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+	recordMap, err := convertToSliceOfMaps(records, updateOperation)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
@@ -192,7 +216,7 @@ func doUpdateComposite(
 		}
 	}
 
-	uri := "/services/data/" + apiVersion + "/composite/sobjects"
+	uri := sf.config.dataPath() + "/composite/sobjects"
 	compReq, compositeErr := createCompositeRequestForCollection(
 		http.MethodPatch,
 		uri,
@@ -203,7 +227,7 @@ func doUpdateComposite(
 	if compositeErr != nil {
 		return SalesforceResults{}, compositeErr
 	}
-	results, compositeReqErr := doCompositeRequest(sf, compReq)
+	results, compositeReqErr := doCompositeRequest(ctx, sf, compReq)
 	if compositeReqErr != nil {
 		return SalesforceResults{}, compositeReqErr
 	}
@@ -212,6 +236,7 @@ func doUpdateComposite(
 }
 
 func doUpsertComposite(
+	ctx context.Context,
 	sf *Salesforce,
 	sObjectName string,
 	fieldName string,
@@ -219,7 +244,7 @@ func doUpsertComposite(
 	allOrNone bool,
 	batchSize int,
 ) (SalesforceResults, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+	recordMap, err := convertToSliceOfMaps(records, upsertOperation)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
@@ -228,7 +253,7 @@ func doUpsertComposite(
 		return SalesforceResults{}, err
 	}
 
-	uri := "/services/data/" + apiVersion + "/composite/sobjects/" + sObjectName + "/" + fieldName
+	uri := sf.config.dataPath() + "/composite/sobjects/" + sObjectName + "/" + fieldName
 	compReq, compositeErr := createCompositeRequestForCollection(
 		http.MethodPatch,
 		uri,
@@ -239,7 +264,7 @@ func doUpsertComposite(
 	if compositeErr != nil {
 		return SalesforceResults{}, compositeErr
 	}
-	results, compositeReqErr := doCompositeRequest(sf, compReq)
+	results, compositeReqErr := doCompositeRequest(ctx, sf, compReq)
 	if compositeReqErr != nil {
 		return SalesforceResults{}, compositeReqErr
 	}
@@ -248,13 +273,14 @@ func doUpsertComposite(
 }
 
 func doDeleteComposite(
+	ctx context.Context,
 	sf *Salesforce,
 	sObjectName string,
 	records any,
 	allOrNone bool,
 	batchSize int,
 ) (SalesforceResults, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+	recordMap, err := convertToSliceOfMaps(records, deleteOperation)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
@@ -283,7 +309,7 @@ func doDeleteComposite(
 			}
 		}
 
-		uri := "/services/data/" + apiVersion + "/composite/sobjects/?ids=" + ids + "&allOrNone=" + strconv.FormatBool(
+		uri := sf.config.dataPath() + "/composite/sobjects/?ids=" + ids + "&allOrNone=" + strconv.FormatBool(
 			allOrNone,
 		)
 		subReq := compositeSubRequest{
@@ -300,7 +326,7 @@ func doDeleteComposite(
 		AllOrNone:        allOrNone,
 		CompositeRequest: subReqs,
 	}
-	results, compositeReqErr := doCompositeRequest(sf, compReq)
+	results, compositeReqErr := doCompositeRequest(ctx, sf, compReq)
 	if compositeReqErr != nil {
 		return SalesforceResults{}, compositeReqErr
 	}
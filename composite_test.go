@@ -2,11 +2,13 @@ package salesforce
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -217,6 +219,15 @@ func Test_processCompositeResponse(t *testing.T) {
 		Body:       bodyNoError,
 	}
 
+	haltedBody := `{"compositeResponse":[{"body":[{"errorCode":"PROCESSING_HALTED",` +
+		`"message":"The transaction was rolled back"}],"httpHeaders":{},"httpStatusCode":400,` +
+		`"referenceId":"refObj0"}]}`
+	httpRespHalted := http.Response{
+		Status:     fmt.Sprint(http.StatusOK),
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(haltedBody)),
+	}
+
 	type args struct {
 		resp      http.Response
 		allOrNone bool
@@ -227,6 +238,23 @@ func Test_processCompositeResponse(t *testing.T) {
 		want    SalesforceResults
 		wantErr bool
 	}{
+		{
+			name: "process_halted_subrequest",
+			args: args{
+				resp:      httpRespHalted,
+				allOrNone: true,
+			},
+			want: SalesforceResults{
+				Results: []SalesforceResult{{
+					Errors: []SalesforceErrorMessage{{
+						ErrorCode: "PROCESSING_HALTED",
+						Message:   "The transaction was rolled back",
+					}},
+				}},
+				HasSalesforceErrors: true,
+			},
+			wantErr: false,
+		},
 		{
 			name: "process_500_error",
 			args: args{
@@ -358,7 +386,7 @@ func Test_doCompositeRequest(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := doCompositeRequest(tt.args.sf, tt.args.compReq)
+			got, err := doCompositeRequest(context.Background(), tt.args.sf, tt.args.compReq)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doCompositeRequest() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -438,6 +466,7 @@ func Test_doInsertComposite(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := doInsertComposite(
+				context.Background(),
 				tt.args.sf,
 				tt.args.sObjectName,
 				tt.args.records,
@@ -542,6 +571,7 @@ func Test_doUpdateComposite(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := doUpdateComposite(
+				context.Background(),
 				tt.args.sf,
 				tt.args.sObjectName,
 				tt.args.records,
@@ -650,6 +680,7 @@ func Test_doUpsertComposite(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := doUpsertComposite(
+				context.Background(),
 				tt.args.sf,
 				tt.args.sObjectName,
 				tt.args.fieldName,
@@ -770,6 +801,7 @@ func Test_doDeleteComposite(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := doDeleteComposite(
+				context.Background(),
 				tt.args.sf,
 				tt.args.sObjectName,
 				tt.args.records,
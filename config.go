@@ -14,11 +14,16 @@ type configuration struct {
 	apiVersion                   string
 	batchSizeMax                 int
 	bulkBatchSizeMax             int
-	bulkPollTimeout              time.Duration     // timeout for waiting on bulk job completion
-	httpClient                   *http.Client      // HTTP client (created internally)
-	roundTripper                 http.RoundTripper // Custom round tripper
-	shouldValidateAuthentication bool              // Validate session on client creation
-	httpTimeout                  time.Duration     // HTTP client timeout
+	bulkPollTimeout              time.Duration         // timeout for waiting on bulk job completion
+	bulkPollInterval             time.Duration         // wait before the first poll of a bulk job
+	bulkPollMaxInterval          time.Duration         // upper bound for the poll interval, doubled after every poll
+	bulkProgress                 func(BulkJobProgress) // called on every poll of a bulk job
+	httpClient                   *http.Client          // HTTP client (created internally)
+	roundTripper                 http.RoundTripper     // Custom round tripper
+	shouldValidateAuthentication bool                  // Validate session on client creation
+	httpTimeout                  time.Duration         // HTTP client timeout
+	retryPolicy                  *RetryPolicy          // retry policy for transient failures, nil disables retries
+	tokenStore                   TokenStore            // persists session tokens, nil keeps them in memory only
 }
 
 func (c *configuration) setDefaults() {
@@ -28,6 +33,8 @@ func (c *configuration) setDefaults() {
 	c.batchSizeMax = batchSizeMax
 	c.bulkBatchSizeMax = bulkBatchSizeMax
 	c.bulkPollTimeout = bulkPollTimeout
+	c.bulkPollInterval = bulkPollInterval
+	c.bulkPollMaxInterval = bulkPollInterval
 	c.httpTimeout = httpDefaultTimeout
 }
 
@@ -51,6 +58,11 @@ func (c *configuration) configureHttpClient() {
 	}
 }
 
+// dataPath returns the base path of the REST API for the configured version, e.g. /services/data/v63.0
+func (c *configuration) dataPath() string {
+	return "/services/data/" + c.apiVersion
+}
+
 // Option is a functional configuration option that can return an error
 type Option func(*configuration) error
 
@@ -121,6 +133,34 @@ func WithBulkPollTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithBulkPollInterval sets how often bulk jobs are polled while waiting for them to finish.
+// The interval doubles after every poll up to maxInterval; the default polls every 500ms.
+func WithBulkPollInterval(interval time.Duration, maxInterval time.Duration) Option {
+	return func(c *configuration) error {
+		if interval <= 0 {
+			return errors.New("bulk poll interval must be greater than 0")
+		}
+		if maxInterval < interval {
+			return errors.New("bulk poll max interval must not be less than the interval")
+		}
+		c.bulkPollInterval = interval
+		c.bulkPollMaxInterval = maxInterval
+		return nil
+	}
+}
+
+// WithBulkProgress calls fn on every poll of a bulk ingest or query job with the state and record counts
+// of the job. fn may be called from several goroutines at once when several jobs are waited for.
+func WithBulkProgress(fn func(BulkJobProgress)) Option {
+	return func(c *configuration) error {
+		if fn == nil {
+			return errors.New("bulk progress function cannot be nil")
+		}
+		c.bulkProgress = fn
+		return nil
+	}
+}
+
 // WithRoundTripper sets a custom round tripper for HTTP requests
 func WithRoundTripper(rt http.RoundTripper) Option {
 	return func(c *configuration) error {
@@ -150,3 +190,25 @@ func WithValidateAuthentication(validate bool) Option {
 		return nil
 	}
 }
+
+// WithRetryPolicy enables automatic retries of transient failures, see DefaultRetryPolicy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *configuration) error {
+		if err := policy.validate(); err != nil {
+			return err
+		}
+		c.retryPolicy = &policy
+		return nil
+	}
+}
+
+// WithTokenStore persists session tokens in the given store so they are reused across restarts and processes
+func WithTokenStore(store TokenStore) Option {
+	return func(c *configuration) error {
+		if store == nil {
+			return errors.New("token store cannot be nil")
+		}
+		c.tokenStore = store
+		return nil
+	}
+}
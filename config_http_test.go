@@ -1,7 +1,14 @@
 package salesforce
 
 import (
+	"context"
+	"encoding/json"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -192,6 +199,125 @@ func TestSalesforceAPIVersionInRequests(t *testing.T) {
 	}
 }
 
+func TestAPIVersionInURLs(t *testing.T) {
+	for _, version := range []string{"v58.0", apiVersion, "v64.0"} {
+		t.Run(version, func(t *testing.T) {
+			var paths []string
+			var compositeBody string
+			server := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					paths = append(paths, r.URL.Path)
+					var resp any
+					switch {
+					case strings.HasSuffix(r.URL.Path, "/composite"):
+						body, _ := io.ReadAll(r.Body)
+						compositeBody = string(body)
+						resp = compositeRequestResult{}
+					case strings.HasSuffix(r.URL.Path, "/query/"):
+						resp = queryResponse{
+							Done:           false,
+							NextRecordsUrl: "/services/data/" + version + "/query/01g-2000",
+						}
+					default:
+						resp = queryResponse{Done: true}
+					}
+					body, _ := json.Marshal(resp)
+					if _, err := w.Write(body); err != nil {
+						panic(err)
+					}
+				}),
+			)
+			defer server.Close()
+
+			sf := buildSalesforceStruct(&authentication{
+				InstanceUrl: server.URL,
+				AccessToken: "accesstokenvalue",
+			})
+			sf.config.apiVersion = version
+
+			if err := sf.Query("SELECT Id FROM Account", &[]map[string]any{}); err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if _, err := sf.DeleteComposite(
+				"Account",
+				[]map[string]any{{"Id": "001"}},
+				200,
+				true,
+			); err != nil {
+				t.Fatalf("DeleteComposite() error = %v", err)
+			}
+
+			wantPaths := []string{
+				"/services/data/" + version + "/query/",
+				"/services/data/" + version + "/query/01g-2000",
+				"/services/data/" + version + "/composite",
+			}
+			if !reflect.DeepEqual(paths, wantPaths) {
+				t.Errorf("request paths = %v, want %v", paths, wantPaths)
+			}
+			if !strings.Contains(compositeBody, "/services/data/"+version+"/composite/sobjects") {
+				t.Errorf("composite sub-request urls do not use %v: %v", version, compositeBody)
+			}
+		})
+	}
+}
+
+func TestSalesforce_ListAPIVersions(t *testing.T) {
+	versions := []APIVersion{
+		{Label: "Winter '24", Url: "/services/data/v59.0", Version: "59.0"},
+		{Label: "Spring '25", Url: "/services/data/v63.0", Version: "63.0"},
+	}
+	server, sfAuth, capturedRequest := setupTestServerWithCapture(versions, http.StatusOK)
+	defer server.Close()
+
+	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	tests := []struct {
+		name    string
+		auth    *authentication
+		want    []APIVersion
+		wantErr bool
+	}{
+		{
+			name:    "list_versions",
+			auth:    &sfAuth,
+			want:    versions,
+			wantErr: false,
+		},
+		{
+			name:    "http_error",
+			auth:    &badSfAuth,
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "validation_fail",
+			auth:    nil,
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildSalesforceStruct(tt.auth).ListAPIVersions()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ListAPIVersions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListAPIVersions() = %v, want %v", got, tt.want)
+			}
+			if !tt.wantErr && (*capturedRequest).URL.Path != "/services/data" {
+				t.Errorf(
+					"ListAPIVersions() path = %v, want /services/data",
+					(*capturedRequest).URL.Path,
+				)
+			}
+		})
+	}
+}
+
 func TestWithHTTPTimeout(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -300,3 +426,60 @@ func TestWithValidateAuthentication(t *testing.T) {
 		})
 	}
 }
+
+type countingRoundTripper struct {
+	calls atomic.Int32
+}
+
+func (rt *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.calls.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestRoundTripperUsedForAuthentication(t *testing.T) {
+	server, sfAuth := setupTestServer(authentication{
+		AccessToken: "1234",
+		InstanceUrl: "example.com",
+	}, http.StatusOK)
+	defer server.Close()
+
+	t.Run("init", func(t *testing.T) {
+		rt := &countingRoundTripper{}
+		_, err := Init(
+			Creds{Domain: server.URL, ConsumerKey: "key", ConsumerSecret: "secret"},
+			WithRoundTripper(rt),
+		)
+		if err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+		if got := rt.calls.Load(); got != 1 {
+			t.Errorf("Init() round tripper calls = %v, want 1", got)
+		}
+	})
+
+	t.Run("refresh_session", func(t *testing.T) {
+		rt := &countingRoundTripper{}
+		config := getDefaultConfig(t)
+		config.roundTripper = rt
+		config.configureHttpClient()
+		auth := sfAuth
+		auth.grantType = grantTypeClientCredentials
+		if err := refreshSession(context.Background(), &auth, config); err != nil {
+			t.Fatalf("refreshSession() error = %v", err)
+		}
+		if got := rt.calls.Load(); got != 1 {
+			t.Errorf("refreshSession() round tripper calls = %v, want 1", got)
+		}
+	})
+
+	t.Run("web_server_flow_exchange", func(t *testing.T) {
+		rt := &countingRoundTripper{}
+		flow := WebServerFlow{Domain: server.URL, ConsumerKey: "key"}
+		if _, err := flow.Exchange(context.Background(), "code", nil, WithRoundTripper(rt)); err != nil {
+			t.Fatalf("Exchange() error = %v", err)
+		}
+		if got := rt.calls.Load(); got != 1 {
+			t.Errorf("Exchange() round tripper calls = %v, want 1", got)
+		}
+	})
+}
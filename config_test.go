@@ -232,6 +232,151 @@ func TestWithBulkPollTimeout(t *testing.T) {
 	}
 }
 
+func TestWithBulkPollInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		interval    time.Duration
+		maxInterval time.Duration
+		wantErr     bool
+	}{
+		{
+			name:        "valid_backoff",
+			interval:    time.Second,
+			maxInterval: 30 * time.Second,
+			wantErr:     false,
+		},
+		{
+			name:        "constant_interval",
+			interval:    2 * time.Second,
+			maxInterval: 2 * time.Second,
+			wantErr:     false,
+		},
+		{
+			name:        "zero_interval",
+			interval:    0,
+			maxInterval: time.Second,
+			wantErr:     true,
+		},
+		{
+			name:        "max_less_than_interval",
+			interval:    time.Second,
+			maxInterval: time.Millisecond,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := configuration{}
+			config.setDefaults()
+
+			err := WithBulkPollInterval(tt.interval, tt.maxInterval)(&config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithBulkPollInterval() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr &&
+				(config.bulkPollInterval != tt.interval || config.bulkPollMaxInterval != tt.maxInterval) {
+				t.Errorf(
+					"WithBulkPollInterval() = %v, %v, want %v, %v",
+					config.bulkPollInterval,
+					config.bulkPollMaxInterval,
+					tt.interval,
+					tt.maxInterval,
+				)
+			}
+		})
+	}
+}
+
+func TestWithBulkProgress(t *testing.T) {
+	config := configuration{}
+	config.setDefaults()
+	if config.bulkProgress != nil {
+		t.Error("Expected bulkProgress default to be nil")
+	}
+	if err := WithBulkProgress(nil)(&config); err == nil {
+		t.Error("WithBulkProgress() expected error for nil function")
+	}
+	if err := WithBulkProgress(func(BulkJobProgress) {})(&config); err != nil {
+		t.Errorf("WithBulkProgress() error = %v", err)
+	}
+	if config.bulkProgress == nil {
+		t.Error("WithBulkProgress() did not set the progress function")
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		wantErr bool
+	}{
+		{
+			name:    "default_policy",
+			policy:  DefaultRetryPolicy(),
+			wantErr: false,
+		},
+		{
+			name:    "zero_attempts",
+			policy:  RetryPolicy{MaxAttempts: 0},
+			wantErr: true,
+		},
+		{
+			name:    "negative_backoff",
+			policy:  RetryPolicy{MaxAttempts: 2, InitialBackoff: -1 * time.Second},
+			wantErr: true,
+		},
+		{
+			name: "max_backoff_below_initial",
+			policy: RetryPolicy{
+				MaxAttempts:    2,
+				InitialBackoff: time.Second,
+				MaxBackoff:     time.Millisecond,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := configuration{}
+			config.setDefaults()
+
+			option := WithRetryPolicy(tt.policy)
+			err := option(&config)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithRetryPolicy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && config.retryPolicy.MaxAttempts != tt.policy.MaxAttempts {
+				t.Errorf(
+					"WithRetryPolicy() = %v, want %v",
+					config.retryPolicy.MaxAttempts,
+					tt.policy.MaxAttempts,
+				)
+			}
+		})
+	}
+}
+
+func TestWithTokenStore(t *testing.T) {
+	config := configuration{}
+	config.setDefaults()
+
+	if err := WithTokenStore(nil)(&config); err == nil {
+		t.Error("WithTokenStore(nil) expected error")
+	}
+
+	store := NewMemoryTokenStore()
+	if err := WithTokenStore(store)(&config); err != nil {
+		t.Errorf("WithTokenStore() error = %v", err)
+	}
+	if config.tokenStore != store {
+		t.Errorf("WithTokenStore() = %v, want %v", config.tokenStore, store)
+	}
+}
+
 func TestConfigurationDefaults(t *testing.T) {
 	config := configuration{}
 	config.setDefaults()
@@ -270,4 +415,8 @@ func TestConfigurationDefaults(t *testing.T) {
 			config.bulkPollTimeout,
 		)
 	}
+
+	if config.retryPolicy != nil {
+		t.Errorf("Expected retryPolicy default to be nil, got %v", config.retryPolicy)
+	}
 }
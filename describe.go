@@ -0,0 +1,188 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// DescribeGlobalResult lists the objects available in the org
+type DescribeGlobalResult struct {
+	Encoding     string           `json:"encoding"`
+	MaxBatchSize int              `json:"maxBatchSize"`
+	SObjects     []SObjectSummary `json:"sobjects"`
+}
+
+// SObjectSummary is the basic metadata of an object returned by DescribeGlobal
+type SObjectSummary struct {
+	Name         string            `json:"name"`
+	Label        string            `json:"label"`
+	LabelPlural  string            `json:"labelPlural"`
+	KeyPrefix    string            `json:"keyPrefix"`
+	Custom       bool              `json:"custom"`
+	Createable   bool              `json:"createable"`
+	Updateable   bool              `json:"updateable"`
+	Deletable    bool              `json:"deletable"`
+	Queryable    bool              `json:"queryable"`
+	Retrieveable bool              `json:"retrieveable"`
+	Searchable   bool              `json:"searchable"`
+	Urls         map[string]string `json:"urls"`
+}
+
+// SObjectDescribe is the full metadata of an object returned by DescribeSObject
+type SObjectDescribe struct {
+	Name               string              `json:"name"`
+	Label              string              `json:"label"`
+	LabelPlural        string              `json:"labelPlural"`
+	KeyPrefix          string              `json:"keyPrefix"`
+	Custom             bool                `json:"custom"`
+	Createable         bool                `json:"createable"`
+	Updateable         bool                `json:"updateable"`
+	Deletable          bool                `json:"deletable"`
+	Queryable          bool                `json:"queryable"`
+	Fields             []SObjectField      `json:"fields"`
+	ChildRelationships []ChildRelationship `json:"childRelationships"`
+	RecordTypeInfos    []RecordTypeInfo    `json:"recordTypeInfos"`
+}
+
+// Field returns the field with the given API name, or false if the object has no such field
+func (d SObjectDescribe) Field(name string) (SObjectField, bool) {
+	for _, field := range d.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return SObjectField{}, false
+}
+
+// SObjectField describes a field of an object
+type SObjectField struct {
+	Name               string          `json:"name"`
+	Label              string          `json:"label"`
+	Type               string          `json:"type"`     // e.g. string, picklist, reference, double, date
+	SoapType           string          `json:"soapType"` // e.g. xsd:string, xsd:double, tns:ID
+	Length             int             `json:"length"`
+	ByteLength         int             `json:"byteLength"`
+	Precision          int             `json:"precision"`
+	Scale              int             `json:"scale"`
+	Digits             int             `json:"digits"`
+	Custom             bool            `json:"custom"`
+	Nillable           bool            `json:"nillable"`
+	Createable         bool            `json:"createable"`
+	Updateable         bool            `json:"updateable"`
+	Unique             bool            `json:"unique"`
+	ExternalId         bool            `json:"externalId"`
+	IdLookup           bool            `json:"idLookup"`
+	Calculated         bool            `json:"calculated"`
+	DefaultedOnCreate  bool            `json:"defaultedOnCreate"`
+	RelationshipName   string          `json:"relationshipName"`
+	ReferenceTo        []string        `json:"referenceTo"`
+	PicklistValues     []PicklistValue `json:"picklistValues"`
+	RestrictedPicklist bool            `json:"restrictedPicklist"`
+}
+
+// PicklistValue is one of the values of a picklist field
+type PicklistValue struct {
+	Value        string `json:"value"`
+	Label        string `json:"label"`
+	Active       bool   `json:"active"`
+	DefaultValue bool   `json:"defaultValue"`
+	ValidFor     string `json:"validFor"`
+}
+
+// ChildRelationship describes a relationship from another object that looks up to the described object
+type ChildRelationship struct {
+	ChildSObject     string `json:"childSObject"`
+	Field            string `json:"field"`
+	RelationshipName string `json:"relationshipName"`
+	CascadeDelete    bool   `json:"cascadeDelete"`
+}
+
+// RecordTypeInfo describes a record type of an object
+type RecordTypeInfo struct {
+	RecordTypeId             string `json:"recordTypeId"`
+	Name                     string `json:"name"`
+	DeveloperName            string `json:"developerName"`
+	Active                   bool   `json:"active"`
+	Available                bool   `json:"available"`
+	DefaultRecordTypeMapping bool   `json:"defaultRecordTypeMapping"`
+	Master                   bool   `json:"master"`
+}
+
+// describeCache keeps describe responses so they are only downloaded again when the metadata changed
+type describeCache struct {
+	mu      sync.Mutex
+	entries map[string]describeCacheEntry
+}
+
+type describeCacheEntry struct {
+	lastModified string
+	body         []byte
+}
+
+func newDescribeCache() *describeCache {
+	return &describeCache{entries: map[string]describeCacheEntry{}}
+}
+
+func (c *describeCache) get(uri string) (describeCacheEntry, bool) {
+	if c == nil {
+		return describeCacheEntry{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[uri]
+	return entry, ok
+}
+
+func (c *describeCache) set(uri string, entry describeCacheEntry) {
+	if c == nil || entry.lastModified == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[uri] = entry
+}
+
+// doDescribe gets a describe resource, sending If-Modified-Since for cached responses
+// and decoding the cached body when Salesforce responds that it is not modified.
+func doDescribe(ctx context.Context, sf *Salesforce, uri string, result any) error {
+	var opts []RequestOption
+	cached, isCached := sf.describes.get(uri)
+	if isCached {
+		opts = append(opts, WithHeader("If-Modified-Since", cached.lastModified))
+	}
+
+	resp, err := doRequest(ctx, sf.auth, sf.config, requestPayload{
+		method:   http.MethodGet,
+		uri:      uri,
+		content:  jsonType,
+		compress: sf.config.compressionHeaders,
+		options:  opts,
+	})
+	if err != nil {
+		return err
+	}
+	respBody, readErr := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if readErr != nil {
+		return readErr
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		if !isCached {
+			return errors.New("describe not modified but no cached response")
+		}
+		respBody = cached.body
+	} else {
+		lastModified := resp.Header.Get("Last-Modified")
+		if lastModified == "" {
+			lastModified = resp.Header.Get("Date") // unchanged since it was retrieved
+		}
+		sf.describes.set(uri, describeCacheEntry{lastModified: lastModified, body: respBody})
+	}
+
+	return json.Unmarshal(respBody, result)
+}
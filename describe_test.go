@@ -0,0 +1,148 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const describeLastModified = "Wed, 21 Oct 2015 07:28:00 GMT"
+
+func setupDescribeServer(
+	t *testing.T,
+	body any,
+) (*httptest.Server, authentication, *[]string) {
+	var conditions []string
+	respBody, _ := json.Marshal(body)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifModifiedSince := r.Header.Get("If-Modified-Since")
+		conditions = append(conditions, ifModifiedSince)
+		if ifModifiedSince == describeLastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", describeLastModified)
+		if _, err := w.Write(respBody); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}
+	return server, sfAuth, &conditions
+}
+
+func TestSalesforce_DescribeSObject(t *testing.T) {
+	describe := SObjectDescribe{
+		Name:       "Account",
+		Label:      "Account",
+		Createable: true,
+		Fields: []SObjectField{
+			{Name: "Id", Type: "id", Length: 18},
+			{
+				Name:       "Rating",
+				Type:       "picklist",
+				Updateable: true,
+				PicklistValues: []PicklistValue{
+					{Value: "Hot", Label: "Hot", Active: true},
+					{Value: "Cold", Label: "Cold", Active: true},
+				},
+			},
+			{
+				Name:             "ParentId",
+				Type:             "reference",
+				RelationshipName: "Parent",
+				ReferenceTo:      []string{"Account"},
+			},
+		},
+		ChildRelationships: []ChildRelationship{
+			{ChildSObject: "Contact", Field: "AccountId", RelationshipName: "Contacts"},
+		},
+		RecordTypeInfos: []RecordTypeInfo{
+			{RecordTypeId: "012000000000000AAA", Name: "Master", Master: true},
+		},
+	}
+	server, sfAuth, conditions := setupDescribeServer(t, describe)
+	defer server.Close()
+
+	badServer, badSfAuth := setupTestServer("", http.StatusNotFound)
+	defer badServer.Close()
+
+	t.Run("cached_describe", func(t *testing.T) {
+		sf := buildSalesforceStruct(&sfAuth)
+		for range 2 {
+			got, err := sf.DescribeSObject("Account")
+			if err != nil {
+				t.Fatalf("Salesforce.DescribeSObject() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, describe) {
+				t.Errorf("Salesforce.DescribeSObject() = %v, want %v", got, describe)
+			}
+		}
+		if want := []string{"", describeLastModified}; !reflect.DeepEqual(*conditions, want) {
+			t.Errorf("If-Modified-Since headers = %v, want %v", *conditions, want)
+		}
+	})
+
+	t.Run("field", func(t *testing.T) {
+		field, ok := describe.Field("Rating")
+		if !ok || len(field.PicklistValues) != 2 {
+			t.Errorf("SObjectDescribe.Field() = %v, %v", field, ok)
+		}
+		if _, ok := describe.Field("Missing__c"); ok {
+			t.Error("SObjectDescribe.Field() found missing field")
+		}
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		if _, err := buildSalesforceStruct(&badSfAuth).DescribeSObject("Missing__c"); err == nil {
+			t.Error("Salesforce.DescribeSObject() expected error")
+		}
+	})
+
+	t.Run("empty_name", func(t *testing.T) {
+		if _, err := buildSalesforceStruct(&sfAuth).DescribeSObject(""); err == nil {
+			t.Error("Salesforce.DescribeSObject() expected error")
+		}
+	})
+
+	t.Run("validation_fail", func(t *testing.T) {
+		if _, err := buildSalesforceStruct(nil).DescribeSObject("Account"); err == nil {
+			t.Error("Salesforce.DescribeSObject() expected validation error")
+		}
+	})
+}
+
+func TestSalesforce_DescribeGlobal(t *testing.T) {
+	global := DescribeGlobalResult{
+		Encoding:     "UTF-8",
+		MaxBatchSize: 200,
+		SObjects: []SObjectSummary{
+			{Name: "Account", Label: "Account", KeyPrefix: "001", Queryable: true},
+			{Name: "Invoice__c", Label: "Invoice", Custom: true, Createable: true},
+		},
+	}
+	server, sfAuth, conditions := setupDescribeServer(t, global)
+	defer server.Close()
+
+	sf := buildSalesforceStruct(&sfAuth)
+	for range 2 {
+		got, err := sf.DescribeGlobal()
+		if err != nil {
+			t.Fatalf("Salesforce.DescribeGlobal() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, global) {
+			t.Errorf("Salesforce.DescribeGlobal() = %v, want %v", got, global)
+		}
+	}
+	if want := []string{"", describeLastModified}; !reflect.DeepEqual(*conditions, want) {
+		t.Errorf("If-Modified-Since headers = %v, want %v", *conditions, want)
+	}
+
+	if _, err := buildSalesforceStruct(nil).DescribeGlobal(); err == nil {
+		t.Error("Salesforce.DescribeGlobal() expected validation error")
+	}
+}
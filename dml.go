@@ -1,12 +1,16 @@
 package salesforce
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/go-viper/mapstructure/v2"
 )
@@ -16,7 +20,7 @@ type sObjectCollection struct {
 	Records   []map[string]any `json:"records"`
 }
 
-func convertToMap(obj any) (map[string]any, error) {
+func convertToMap(obj any, operation string) (map[string]any, error) {
 	var recordMap map[string]any
 	if _, ok := obj.(map[string]any); ok {
 		recordMap = obj.(map[string]any)
@@ -25,11 +29,12 @@ func convertToMap(obj any) (map[string]any, error) {
 		if err != nil {
 			return nil, errors.New("issue decoding salesforce object, need a key value pair (custom struct or map)")
 		}
+		omitReadOnlyFields(reflect.TypeOf(obj), operation, recordMap)
 	}
 	return recordMap, nil
 }
 
-func convertToSliceOfMaps(obj any) ([]map[string]any, error) {
+func convertToSliceOfMaps(obj any, operation string) ([]map[string]any, error) {
 	var recordMap []map[string]any
 	if _, ok := obj.(map[string]any); ok {
 		recordMap = obj.([]map[string]any)
@@ -38,10 +43,101 @@ func convertToSliceOfMaps(obj any) ([]map[string]any, error) {
 		if err != nil {
 			return nil, errors.New("issue decoding salesforce object, need a key value pair (custom struct or map)")
 		}
+		if t := reflect.TypeOf(obj); t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			omitReadOnlyFields(t.Elem(), operation, recordMap...)
+		}
 	}
 	return recordMap, nil
 }
 
+// omitReadOnlyFields removes the fields of struct type t that Salesforce rejects for operation from records.
+// Fields tagged readonly, e.g. `salesforce:"CreatedDate,omitempty,readonly"`, are never sent and fields
+// tagged createonly are not sent on update. sfgen adds these options from the createable and updateable
+// properties of the object describe.
+func omitReadOnlyFields(t reflect.Type, operation string, records ...map[string]any) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return
+	}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		_, options, _ := strings.Cut(field.Tag.Get("salesforce"), ",")
+		tagOptions := strings.Split(options, ",")
+		if slices.Contains(tagOptions, "readonly") ||
+			(operation == updateOperation && slices.Contains(tagOptions, "createonly")) {
+			for _, record := range records {
+				delete(record, salesforceFieldName(field))
+			}
+		}
+	}
+}
+
+// assignResults writes the Id of each result into the record at the same position of the slice that
+// records points to. Struct records also get the errors of the result when they have a field of type
+// []SalesforceErrorMessage, which should be tagged `salesforce:"-"` so it is not sent to Salesforce.
+func assignResults(records any, results []SalesforceResult) error {
+	if err := validateOfTypePointerToSlice(records); err != nil {
+		return err
+	}
+	slice := reflect.ValueOf(records).Elem()
+	for i := 0; i < slice.Len() && i < len(results); i++ {
+		if err := assignResult(slice.Index(i), results[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func assignResult(record reflect.Value, result SalesforceResult) error {
+	for record.Kind() == reflect.Pointer || record.Kind() == reflect.Interface {
+		if record.IsNil() {
+			return nil
+		}
+		record = record.Elem()
+	}
+
+	switch record.Kind() {
+	case reflect.Map:
+		recordMap, ok := record.Interface().(map[string]any)
+		if !ok {
+			return errors.New(
+				"expected records of type map[string]any, got: " + record.Type().String(),
+			)
+		}
+		if result.Id != "" {
+			recordMap["Id"] = result.Id
+		}
+	case reflect.Struct:
+		for i := range record.NumField() {
+			field := record.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Type == reflect.TypeOf([]SalesforceErrorMessage{}) {
+				record.Field(i).Set(reflect.ValueOf(result.Errors))
+			} else if salesforceFieldName(field) == "Id" && field.Type.Kind() == reflect.String &&
+				result.Id != "" {
+				record.Field(i).SetString(result.Id)
+			}
+		}
+	default:
+		return errors.New("expected a struct or map record, got: " + record.Kind().String())
+	}
+	return nil
+}
+
+// salesforceFieldName returns the name a struct field is sent to Salesforce with
+func salesforceFieldName(field reflect.StructField) string {
+	for _, tagName := range []string{"salesforce", "mapstructure"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(tagName), ","); name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
 func processSalesforceResponse(resp http.Response) ([]SalesforceResult, error) {
 	results := []SalesforceResult{}
 	responseData, err := io.ReadAll(resp.Body)
@@ -57,6 +153,7 @@ func processSalesforceResponse(resp http.Response) ([]SalesforceResult, error) {
 }
 
 func doBatchedRequestsForCollection(
+	ctx context.Context,
 	sf *Salesforce,
 	method string,
 	url string,
@@ -84,7 +181,7 @@ func doBatchedRequestsForCollection(
 			return SalesforceResults{Results: results}, err
 		}
 
-		resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		resp, err := doRequest(ctx, sf.auth, sf.config, requestPayload{
 			method:   method,
 			uri:      url,
 			content:  jsonType,
@@ -180,8 +277,13 @@ func convertToString(value any) (string, bool) {
 	}
 }
 
-func doInsertOne(sf *Salesforce, sObjectName string, record any) (SalesforceResult, error) {
-	recordMap, err := convertToMap(record)
+func doInsertOne(
+	ctx context.Context,
+	sf *Salesforce,
+	sObjectName string,
+	record any,
+) (SalesforceResult, error) {
+	recordMap, err := convertToMap(record, insertOperation)
 	if err != nil {
 		return SalesforceResult{}, err
 	}
@@ -193,7 +295,7 @@ func doInsertOne(sf *Salesforce, sObjectName string, record any) (SalesforceResu
 		return SalesforceResult{}, err
 	}
 
-	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+	resp, err := doRequest(ctx, sf.auth, sf.config, requestPayload{
 		method:   http.MethodPost,
 		uri:      "/sobjects/" + sObjectName,
 		content:  jsonType,
@@ -213,8 +315,8 @@ func doInsertOne(sf *Salesforce, sObjectName string, record any) (SalesforceResu
 	return data, nil
 }
 
-func doUpdateOne(sf *Salesforce, sObjectName string, record any) error {
-	recordMap, err := convertToMap(record)
+func doUpdateOne(ctx context.Context, sf *Salesforce, sObjectName string, record any) error {
+	recordMap, err := convertToMap(record, updateOperation)
 	if err != nil {
 		return err
 	}
@@ -232,7 +334,7 @@ func doUpdateOne(sf *Salesforce, sObjectName string, record any) error {
 		return err
 	}
 
-	_, err = doRequest(sf.auth, sf.config, requestPayload{
+	_, err = doRequest(ctx, sf.auth, sf.config, requestPayload{
 		method:   http.MethodPatch,
 		uri:      "/sobjects/" + sObjectName + "/" + recordId,
 		content:  jsonType,
@@ -247,12 +349,13 @@ func doUpdateOne(sf *Salesforce, sObjectName string, record any) error {
 }
 
 func doUpsertOne(
+	ctx context.Context,
 	sf *Salesforce,
 	sObjectName string,
 	fieldName string,
 	record any,
 ) (SalesforceResult, error) {
-	recordMap, err := convertToMap(record)
+	recordMap, err := convertToMap(record, upsertOperation)
 	if err != nil {
 		return SalesforceResult{}, err
 	}
@@ -282,7 +385,7 @@ func doUpsertOne(
 		return SalesforceResult{}, errors.New("external id should be a string or number")
 	}
 
-	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+	resp, err := doRequest(ctx, sf.auth, sf.config, requestPayload{
 		method:   http.MethodPatch,
 		uri:      uri,
 		content:  jsonType,
@@ -302,8 +405,8 @@ func doUpsertOne(
 	return data, nil
 }
 
-func doDeleteOne(sf *Salesforce, sObjectName string, record any) error {
-	recordMap, err := convertToMap(record)
+func doDeleteOne(ctx context.Context, sf *Salesforce, sObjectName string, record any) error {
+	recordMap, err := convertToMap(record, deleteOperation)
 	if err != nil {
 		return err
 	}
@@ -313,7 +416,7 @@ func doDeleteOne(sf *Salesforce, sObjectName string, record any) error {
 		return errors.New("salesforce id not found in object data")
 	}
 
-	_, err = doRequest(sf.auth, sf.config, requestPayload{
+	_, err = doRequest(ctx, sf.auth, sf.config, requestPayload{
 		method:   http.MethodDelete,
 		uri:      "/sobjects/" + sObjectName + "/" + recordId,
 		content:  jsonType,
@@ -327,12 +430,13 @@ func doDeleteOne(sf *Salesforce, sObjectName string, record any) error {
 }
 
 func doInsertCollection(
+	ctx context.Context,
 	sf *Salesforce,
 	sObjectName string,
 	records any,
 	batchSize int,
 ) (SalesforceResults, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+	recordMap, err := convertToSliceOfMaps(records, insertOperation)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
@@ -342,6 +446,7 @@ func doInsertCollection(
 	}
 
 	return doBatchedRequestsForCollection(
+		ctx,
 		sf,
 		http.MethodPost,
 		"/composite/sobjects/",
@@ -351,12 +456,13 @@ func doInsertCollection(
 }
 
 func doUpdateCollection(
+	ctx context.Context,
 	sf *Salesforce,
 	sObjectName string,
 	records any,
 	batchSize int,
 ) (SalesforceResults, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+	recordMap, err := convertToSliceOfMaps(records, updateOperation)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
@@ -369,6 +475,7 @@ func doUpdateCollection(
 	}
 
 	return doBatchedRequestsForCollection(
+		ctx,
 		sf,
 		http.MethodPatch,
 		"/composite/sobjects/",
@@ -378,13 +485,14 @@ func doUpdateCollection(
 }
 
 func doUpsertCollection(
+	ctx context.Context,
 	sf *Salesforce,
 	sObjectName string,
 	fieldName string,
 	records any,
 	batchSize int,
 ) (SalesforceResults, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+	recordMap, err := convertToSliceOfMaps(records, upsertOperation)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
@@ -393,16 +501,17 @@ func doUpsertCollection(
 		return SalesforceResults{}, err
 	}
 	uri := "/composite/sobjects/" + sObjectName + "/" + fieldName
-	return doBatchedRequestsForCollection(sf, http.MethodPatch, uri, batchSize, recordMap)
+	return doBatchedRequestsForCollection(ctx, sf, http.MethodPatch, uri, batchSize, recordMap)
 }
 
 func doDeleteCollection(
+	ctx context.Context,
 	sf *Salesforce,
 	sObjectName string,
 	records any,
 	batchSize int,
 ) (SalesforceResults, error) {
-	recordMap, err := convertToSliceOfMaps(records)
+	recordMap, err := convertToSliceOfMaps(records, deleteOperation)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
@@ -437,7 +546,7 @@ func doDeleteCollection(
 	results := []SalesforceResult{}
 
 	for i := range batchedIds {
-		resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		resp, err := doRequest(ctx, sf.auth, sf.config, requestPayload{
 			method:   http.MethodDelete,
 			uri:      "/composite/sobjects/?ids=" + batchedIds[i] + "&allOrNone=false",
 			content:  jsonType,
@@ -2,6 +2,7 @@ package salesforce
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,8 +17,21 @@ func Test_convertToMap(t *testing.T) {
 		Id   string
 		Name string
 	}
+	type contact struct {
+		Id          string `salesforce:"Id,omitempty"`
+		LastName    string `salesforce:"LastName,omitempty"`
+		AccountId   string `salesforce:"AccountId,omitempty,createonly"`
+		CreatedDate string `salesforce:"CreatedDate,omitempty,readonly"`
+	}
+	queried := contact{
+		Id:          "1234",
+		LastName:    "Smith",
+		AccountId:   "0015000000VALDtAAP",
+		CreatedDate: "2024-01-01T00:00:00.000+0000",
+	}
 	type args struct {
-		obj any
+		obj       any
+		operation string
 	}
 	tests := []struct {
 		name    string
@@ -43,10 +57,38 @@ func Test_convertToMap(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "omit_read_only_fields_on_insert",
+			args: args{obj: queried, operation: insertOperation},
+			want: map[string]any{
+				"Id":        "1234",
+				"LastName":  "Smith",
+				"AccountId": "0015000000VALDtAAP",
+			},
+			wantErr: false,
+		},
+		{
+			name: "omit_create_only_fields_on_update",
+			args: args{obj: &queried, operation: updateOperation},
+			want: map[string]any{
+				"Id":       "1234",
+				"LastName": "Smith",
+			},
+			wantErr: false,
+		},
+		{
+			name: "keep_map_keys",
+			args: args{
+				obj:       map[string]any{"Id": "1234", "CreatedDate": "2024-01-01"},
+				operation: updateOperation,
+			},
+			want:    map[string]any{"Id": "1234", "CreatedDate": "2024-01-01"},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := convertToMap(tt.args.obj)
+			got, err := convertToMap(tt.args.obj, tt.args.operation)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("convertToMap() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -63,8 +105,18 @@ func Test_convertToSliceOfMaps(t *testing.T) {
 		Id   string
 		Name string
 	}
+	type contact struct {
+		Id        string `salesforce:"Id,omitempty"`
+		LastName  string `salesforce:"LastName,omitempty"`
+		AccountId string `salesforce:"AccountId,omitempty,createonly"`
+		IsDeleted bool   `salesforce:"IsDeleted,omitempty,readonly"`
+		Account   *struct {
+			Name string `salesforce:"Name,omitempty"`
+		} `salesforce:"Account,omitempty,readonly"`
+	}
 	type args struct {
-		obj any
+		obj       any
+		operation string
 	}
 	tests := []struct {
 		name    string
@@ -102,10 +154,32 @@ func Test_convertToSliceOfMaps(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "omit_read_only_fields_of_pointers",
+			args: args{
+				obj: []*contact{
+					{
+						Id:        "1234",
+						LastName:  "Smith",
+						AccountId: "0015000000VALDtAAP",
+						IsDeleted: true,
+					},
+					{Id: "5678", LastName: "Jones", Account: &struct {
+						Name string `salesforce:"Name,omitempty"`
+					}{Name: "Acme"}},
+				},
+				operation: updateOperation,
+			},
+			want: []map[string]any{
+				{"Id": "1234", "LastName": "Smith"},
+				{"Id": "5678", "LastName": "Jones"},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := convertToSliceOfMaps(tt.args.obj)
+			got, err := convertToSliceOfMaps(tt.args.obj, tt.args.operation)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("convertToSliceOfMaps() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -291,6 +365,7 @@ func Test_doBatchedRequestsForCollection(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := doBatchedRequestsForCollection(
+				context.Background(),
 				tt.args.sf,
 				tt.args.method,
 				tt.args.url,
@@ -372,7 +447,12 @@ func Test_doInsertOne(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := doInsertOne(tt.args.sf, tt.args.sObjectName, tt.args.record)
+			got, err := doInsertOne(
+				context.Background(),
+				tt.args.sf,
+				tt.args.sObjectName,
+				tt.args.record,
+			)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doInsertOne() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -441,7 +521,7 @@ func Test_doUpdateOne(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := doUpdateOne(tt.args.sf, tt.args.sObjectName, tt.args.record); (err != nil) != tt.wantErr {
+			if err := doUpdateOne(context.Background(), tt.args.sf, tt.args.sObjectName, tt.args.record); (err != nil) != tt.wantErr {
 				t.Errorf("doUpdateOne() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -521,6 +601,7 @@ func Test_doUpsertOne(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := doUpsertOne(
+				context.Background(),
 				tt.args.sf,
 				tt.args.sObjectName,
 				tt.args.fieldName,
@@ -591,7 +672,7 @@ func Test_doDeleteOne(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := doDeleteOne(tt.args.sf, tt.args.sObjectName, tt.args.record); (err != nil) != tt.wantErr {
+			if err := doDeleteOne(context.Background(), tt.args.sf, tt.args.sObjectName, tt.args.record); (err != nil) != tt.wantErr {
 				t.Errorf("doDeleteOne() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -660,6 +741,7 @@ func Test_doInsertCollection(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := doInsertCollection(
+				context.Background(),
 				tt.args.sf,
 				tt.args.sObjectName,
 				tt.args.records,
@@ -740,6 +822,7 @@ func Test_doUpdateCollection(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := doUpdateCollection(
+				context.Background(),
 				tt.args.sf,
 				tt.args.sObjectName,
 				tt.args.records,
@@ -823,6 +906,7 @@ func Test_doUpsertCollection(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := doUpsertCollection(
+				context.Background(),
 				tt.args.sf,
 				tt.args.sObjectName,
 				tt.args.fieldName,
@@ -984,6 +1068,7 @@ func Test_doDeleteCollection(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := doDeleteCollection(
+				context.Background(),
 				tt.args.sf,
 				tt.args.sObjectName,
 				tt.args.records,
@@ -998,3 +1083,74 @@ func Test_doDeleteCollection(t *testing.T) {
 		})
 	}
 }
+
+func Test_assignResults(t *testing.T) {
+	type contact struct {
+		Id       string                   `salesforce:"Id,omitempty"`
+		LastName string                   `salesforce:"LastName"`
+		Errors   []SalesforceErrorMessage `salesforce:"-"`
+	}
+	type taggedContact struct {
+		RecordId string `salesforce:"Id"`
+		Id       string `salesforce:"Other_Id__c"`
+	}
+	duplicate := []SalesforceErrorMessage{{StatusCode: "DUPLICATE_VALUE", Message: "duplicate"}}
+	results := []SalesforceResult{
+		{Id: "003A", Success: true},
+		{Errors: duplicate},
+	}
+
+	t.Run("structs", func(t *testing.T) {
+		records := []contact{{LastName: "Stark"}, {LastName: "Banner"}}
+		if err := assignResults(&records, results); err != nil {
+			t.Fatalf("assignResults() error = %v", err)
+		}
+		want := []contact{{Id: "003A", LastName: "Stark"}, {LastName: "Banner", Errors: duplicate}}
+		if !reflect.DeepEqual(records, want) {
+			t.Errorf("assignResults() = %v, want %v", records, want)
+		}
+	})
+
+	t.Run("struct_pointers", func(t *testing.T) {
+		records := []*contact{{LastName: "Stark"}, nil}
+		if err := assignResults(&records, results); err != nil {
+			t.Fatalf("assignResults() error = %v", err)
+		}
+		if records[0].Id != "003A" {
+			t.Errorf("assignResults() = %v", records[0])
+		}
+	})
+
+	t.Run("tagged_id_field", func(t *testing.T) {
+		records := []taggedContact{{Id: "external"}}
+		if err := assignResults(&records, results); err != nil {
+			t.Fatalf("assignResults() error = %v", err)
+		}
+		if want := (taggedContact{RecordId: "003A", Id: "external"}); records[0] != want {
+			t.Errorf("assignResults() = %v, want %v", records[0], want)
+		}
+	})
+
+	t.Run("maps", func(t *testing.T) {
+		records := []map[string]any{{"LastName": "Stark"}}
+		if err := assignResults(&records, results); err != nil {
+			t.Fatalf("assignResults() error = %v", err)
+		}
+		if records[0]["Id"] != "003A" {
+			t.Errorf("assignResults() = %v", records[0])
+		}
+	})
+
+	t.Run("not_a_pointer", func(t *testing.T) {
+		if err := assignResults([]contact{{}}, results); err == nil {
+			t.Error("assignResults() expected error")
+		}
+	})
+
+	t.Run("unsupported_records", func(t *testing.T) {
+		records := []string{"Stark"}
+		if err := assignResults(&records, results); err == nil {
+			t.Error("assignResults() expected error")
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package salesforce
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// APIError is returned when Salesforce rejects a request or a bulk job fails.
+// Use errors.As to access the details, or IsErrorCode to check for a specific Salesforce error code.
+type APIError struct {
+	StatusCode int                      // HTTP status code of the response
+	Errors     []SalesforceErrorMessage // errors parsed from the response body
+	Method     string                   // HTTP method of the failed request
+	URI        string                   // URI of the failed request, relative to the API base like DoRequest
+	Body       []byte                   // raw response body
+}
+
+// Error returns the raw response body when there is one, otherwise a summary of the parsed errors.
+func (e *APIError) Error() string {
+	if len(e.Body) > 0 {
+		return string(e.Body)
+	}
+	messages := make([]string, 0, len(e.Errors))
+	for _, sfError := range e.Errors {
+		if sfError.ErrorCode != "" {
+			messages = append(messages, sfError.ErrorCode+": "+sfError.Message)
+		} else {
+			messages = append(messages, sfError.Message)
+		}
+	}
+	if len(messages) > 0 {
+		return strings.Join(messages, "; ")
+	}
+	return strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode)
+}
+
+// HasErrorCode reports whether any of the errors carries the given code.
+// Record level errors put the code in statusCode rather than errorCode, so both are checked.
+func (e *APIError) HasErrorCode(code string) bool {
+	for _, sfError := range e.Errors {
+		if sfError.ErrorCode == code || sfError.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsErrorCode reports whether err, or any error it wraps, is an *APIError with the given Salesforce error code,
+// e.g. IsErrorCode(err, "DUPLICATE_VALUE").
+func IsErrorCode(err error, code string) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.HasErrorCode(code)
+}
+
+// HasErrorCode reports whether the result failed with the given Salesforce error code.
+func (r SalesforceResult) HasErrorCode(code string) bool {
+	return (&APIError{Errors: r.Errors}).HasErrorCode(code)
+}
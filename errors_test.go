@@ -0,0 +1,175 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func Test_doRequest_returnsAPIError(t *testing.T) {
+	sfErrors := []SalesforceErrorMessage{{
+		Message:   "duplicate value found: ExternalId__c",
+		ErrorCode: "DUPLICATE_VALUE",
+		Fields:    []string{"ExternalId__c"},
+	}}
+	server, sfAuth := setupTestServer(sfErrors, http.StatusBadRequest)
+	defer server.Close()
+	body, _ := json.Marshal(sfErrors)
+
+	_, err := doRequest(context.Background(), &sfAuth, getDefaultConfig(t), requestPayload{
+		method:  http.MethodPost,
+		uri:     "/sobjects/Account",
+		content: jsonType,
+		body:    `{"Name":"test"}`,
+	})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("doRequest() error = %v, want *APIError", err)
+	}
+	want := &APIError{
+		StatusCode: http.StatusBadRequest,
+		Errors:     sfErrors,
+		Method:     http.MethodPost,
+		URI:        "/sobjects/Account",
+		Body:       body,
+	}
+	if !reflect.DeepEqual(apiErr, want) {
+		t.Errorf("doRequest() error = %#v, want %#v", apiErr, want)
+	}
+	if err.Error() != string(body) {
+		t.Errorf("APIError.Error() = %v, want %v", err.Error(), string(body))
+	}
+}
+
+func TestIsErrorCode(t *testing.T) {
+	apiErr := &APIError{
+		StatusCode: http.StatusBadRequest,
+		Errors: []SalesforceErrorMessage{
+			{ErrorCode: "ENTITY_IS_DELETED"},
+			{StatusCode: "REQUIRED_FIELD_MISSING"},
+		},
+	}
+	tests := []struct {
+		name string
+		err  error
+		code string
+		want bool
+	}{
+		{
+			name: "matches_error_code",
+			err:  apiErr,
+			code: "ENTITY_IS_DELETED",
+			want: true,
+		},
+		{
+			name: "matches_status_code",
+			err:  apiErr,
+			code: "REQUIRED_FIELD_MISSING",
+			want: true,
+		},
+		{
+			name: "matches_wrapped_error",
+			err:  fmt.Errorf("upsert failed: %w", apiErr),
+			code: "ENTITY_IS_DELETED",
+			want: true,
+		},
+		{
+			name: "no_match",
+			err:  apiErr,
+			code: "DUPLICATE_VALUE",
+			want: false,
+		},
+		{
+			name: "not_an_api_error",
+			err:  errors.New("ENTITY_IS_DELETED"),
+			code: "ENTITY_IS_DELETED",
+			want: false,
+		},
+		{
+			name: "nil_error",
+			err:  nil,
+			code: "ENTITY_IS_DELETED",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsErrorCode(tt.err, tt.code); got != tt.want {
+				t.Errorf("IsErrorCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *APIError
+		want string
+	}{
+		{
+			name: "raw_body",
+			err:  &APIError{StatusCode: http.StatusBadRequest, Body: []byte("bad request")},
+			want: "bad request",
+		},
+		{
+			name: "parsed_errors",
+			err: &APIError{Errors: []SalesforceErrorMessage{
+				{ErrorCode: "UNABLE_TO_LOCK_ROW", Message: "unable to obtain exclusive access"},
+				{Message: "bulk job aborted"},
+			}},
+			want: "UNABLE_TO_LOCK_ROW: unable to obtain exclusive access; bulk job aborted",
+		},
+		{
+			name: "status_only",
+			err:  &APIError{StatusCode: http.StatusBadGateway},
+			want: "502 Bad Gateway",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("APIError.Error() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isBulkJobDone_returnsAPIError(t *testing.T) {
+	_, err := isBulkJobDone(BulkJobResults{
+		Id:           "1234",
+		State:        jobStateFailed,
+		ErrorMessage: "InvalidBatch : Field name not found : Foo__c",
+	}, ingestJobType)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("isBulkJobDone() error = %v, want *APIError", err)
+	}
+	if apiErr.URI != "/jobs/ingest/1234" {
+		t.Errorf("APIError.URI = %v, want %v", apiErr.URI, "/jobs/ingest/1234")
+	}
+	if err.Error() != "InvalidBatch : Field name not found : Foo__c" {
+		t.Errorf("APIError.Error() = %v", err.Error())
+	}
+}
+
+func Test_doAuth_returnsAPIError(t *testing.T) {
+	server, _ := setupTestServer(map[string]string{
+		"error":             "invalid_grant",
+		"error_description": "authentication failure",
+	}, http.StatusBadRequest)
+	defer server.Close()
+
+	_, err := getDefaultConfig(
+		t,
+	).clientCredentialsFlow(context.Background(), server.URL, "key", "secret")
+	if !IsErrorCode(err, "invalid_grant") {
+		t.Errorf("clientCredentialsFlow() error = %v, want invalid_grant *APIError", err)
+	}
+}
@@ -1,12 +1,9 @@
 package salesforce
 
 import (
-	"encoding/csv"
+	"context"
 	"fmt"
 	"io"
-	"net/http"
-	"strconv"
-	"time"
 
 	"github.com/jszwec/csvutil"
 )
@@ -17,67 +14,92 @@ type IteratorJob interface {
 	Decode(any) error
 }
 
+// ResumableIterator is an IteratorJob over bulk query results that reports its position,
+// so that the iteration can be continued later with ResumeQueryIterator
+type ResumableIterator interface {
+	IteratorJob
+	// JobId returns the query job whose results are being read. It is empty with PK chunking,
+	// as the results of the other chunk jobs cannot be resumed.
+	JobId() string
+	// Locator returns the locator of the page after the one returned by the last call to Next,
+	// "null" when there are no more pages in the job, and empty with PK chunking
+	Locator() string
+	// Close stops the iteration, aborting the PK chunking query jobs that are still running.
+	// It should be called when the results are not read to the end.
+	Close() error
+}
+
 type bulkJobQueryIterator struct {
-	NumberOfRecords int    `json:"Sforce-Numberofrecords"`
-	Locator         string `json:"Sforce-Locator"`
-	auth            *authentication
-	uri             string
+	NumberOfRecords int `json:"Sforce-Numberofrecords"`
+	ctx             context.Context
+	cancel          context.CancelFunc // stops the query jobs and downloads that are still running
+	sf              *Salesforce
+	options         bulkJobOptions
+	chunks          []*bulkQueryChunk // query jobs whose results have not been read
+	pager           *queryResultsPager
+	jobId           string // query job of the current page
+	locator         string // locator of the page after the current one
 	err             error
 	reader          io.ReadCloser
-	config          *configuration
 }
 
-func newBulkJobQueryIterator(sf *Salesforce, bulkJobId string) (*bulkJobQueryIterator, error) {
-	pollErr := waitForJobResults(sf, bulkJobId, queryJobType, (time.Second / 2))
-	if pollErr != nil {
-		return nil, pollErr
-	}
+func newBulkJobQueryIterator(
+	ctx context.Context,
+	cancel context.CancelFunc,
+	sf *Salesforce,
+	chunks []*bulkQueryChunk,
+	options bulkJobOptions,
+) *bulkJobQueryIterator {
 	return &bulkJobQueryIterator{
-		auth:   sf.auth,
-		uri:    "/jobs/query/" + bulkJobId + "/results",
-		config: sf.config,
-	}, nil
+		ctx:     ctx,
+		cancel:  cancel,
+		sf:      sf,
+		options: options,
+		chunks:  chunks,
+	}
 }
 
 func (it *bulkJobQueryIterator) Next() bool {
 	if it.reader != nil {
 		it.err = it.reader.Close()
-		if it.Locator == "" {
+		it.reader = nil
+	}
+	for {
+		if it.pager == nil {
+			if len(it.chunks) == 0 {
+				it.cancel()
+				return false
+			}
+			chunk := it.chunks[0]
+			if err := waitForQueryChunk(it.ctx, chunk, len(it.chunks)); err != nil {
+				it.err = err
+				it.cancel() // aborts the chunk jobs that are still running
+				return false
+			}
+			it.chunks = it.chunks[1:]
+			it.jobId = chunk.jobId
+			it.locator = chunk.locator
+			it.pager = newQueryResultsPager(it.ctx, it.sf, chunk.jobId, chunk.locator, it.options)
+		}
+		page, ok := it.pager.next()
+		if !ok {
+			it.pager = nil
+			continue
+		}
+		if page.err != nil {
+			it.err = page.err
+			it.cancel()
 			return false
 		}
+		it.reader = page.body
+		it.NumberOfRecords = page.numberOfRecords
+		it.locator = page.locator
+		return true
 	}
-	uri := it.uri
-	if it.Locator != "" {
-		uri += "/?locator=" + it.Locator
-	}
-	resp, err := doRequest(
-		it.auth,
-		it.config,
-		requestPayload{
-			method:   http.MethodGet,
-			uri:      uri,
-			content:  jsonType,
-			compress: it.config.compressionHeaders,
-		},
-	)
-	if err != nil {
-		it.err = err
-		return false
-	}
-	it.reader = resp.Body
-
-	it.NumberOfRecords, _ = strconv.Atoi(resp.Header["Sforce-Numberofrecords"][0])
-	if resp.Header["Sforce-Locator"][0] != "null" {
-		it.Locator = resp.Header["Sforce-Locator"][0]
-	} else {
-		it.Locator = ""
-	}
-
-	return true
 }
 
 func (it *bulkJobQueryIterator) Decode(val any) error {
-	dec, err := csvutil.NewDecoder(csv.NewReader(it.reader))
+	dec, err := csvutil.NewDecoder(it.options.format().csvReader(it.reader))
 	if err != nil {
 		return fmt.Errorf("NewDecoder: %w", err)
 	}
@@ -91,3 +113,35 @@ func (it *bulkJobQueryIterator) Decode(val any) error {
 func (it *bulkJobQueryIterator) Error() error {
 	return it.err
 }
+
+func (it *bulkJobQueryIterator) JobId() string {
+	if it.options.chunkSize > 0 {
+		return "" // resuming the current chunk would skip the others
+	}
+	return it.jobId
+}
+
+func (it *bulkJobQueryIterator) Locator() string {
+	if it.options.chunkSize > 0 {
+		return ""
+	}
+	return it.locator
+}
+
+func (it *bulkJobQueryIterator) Close() error {
+	it.cancel()
+	var err error
+	if it.reader != nil {
+		err = it.reader.Close()
+		it.reader = nil
+	}
+	if it.pager != nil {
+		it.pager.close()
+		it.pager = nil
+	}
+	for _, chunk := range it.chunks {
+		<-chunk.done // running jobs are aborted before their chunk is done
+	}
+	it.chunks = nil
+	return err
+}
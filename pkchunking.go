@@ -0,0 +1,267 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	pkChunkRetries = 2  // times a failed chunk job is run again
+	idLength       = 15 // case-sensitive Ids, which sort in the same order as the 18 character Ids
+)
+
+// bulkQueryChunk is one query job of a bulk query, done is closed once the job has completed or failed
+type bulkQueryChunk struct {
+	query   string
+	jobId   string
+	locator string // locator of the first results page to read, empty to read every page
+	err     error
+	done    chan struct{}
+}
+
+// chunkableQuery is a SOQL query split around its top level WHERE condition
+type chunkableQuery struct {
+	prefix    string // SELECT ... FROM object, including USING SCOPE
+	from      string // FROM object, including USING SCOPE
+	condition string // top level WHERE condition, empty without one
+	with      string // WITH clauses after the condition, e.g. WITH SECURITY_ENFORCED
+	suffix    string // clauses after the condition, including WITH and FOR
+}
+
+type soqlWord struct {
+	text  string
+	start int
+	end   int
+}
+
+// topLevelWords returns the words of query that are outside of quotes and parentheses
+func topLevelWords(query string) []soqlWord {
+	var words []soqlWord
+	depth := 0
+	inQuote := false
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case inQuote:
+			if c == '\\' {
+				i += 2
+				continue
+			}
+			inQuote = c != '\''
+		case c == '\'':
+			inQuote = true
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && isSOQLWordChar(c):
+			start := i
+			for i < len(query) && isSOQLWordChar(query[i]) {
+				i++
+			}
+			words = append(words, soqlWord{text: query[start:i], start: start, end: i})
+			continue
+		}
+		i++
+	}
+	return words
+}
+
+func isSOQLWordChar(c byte) bool {
+	return c == '_' || c == '.' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') ||
+		('A' <= c && c <= 'Z')
+}
+
+func parseChunkableQuery(query string) (chunkableQuery, error) {
+	words := topLevelWords(query)
+	from := -1
+	for i, word := range words {
+		if strings.EqualFold(word.text, "FROM") {
+			from = i
+			break
+		}
+	}
+	if from < 0 || from+1 >= len(words) {
+		return chunkableQuery{}, errors.New("PK chunking requires a query with a FROM clause")
+	}
+
+	q := chunkableQuery{}
+	conditionStart, conditionEnd, clausesStart, forStart := -1, len(query), len(query), len(query)
+	for _, word := range words[from+2:] {
+		switch strings.ToUpper(word.text) {
+		case "WHERE":
+			if conditionStart < 0 {
+				clausesStart = word.start
+				conditionStart = word.end
+			}
+		case "GROUP", "ORDER", "LIMIT", "OFFSET":
+			return chunkableQuery{}, fmt.Errorf(
+				"PK chunking does not support queries with %s",
+				strings.ToUpper(word.text),
+			)
+		case "WITH", "FOR":
+			if conditionEnd == len(query) {
+				conditionEnd = word.start
+				clausesStart = min(clausesStart, word.start)
+			}
+			if strings.EqualFold(word.text, "FOR") && forStart == len(query) {
+				forStart = word.start
+			}
+		}
+	}
+	q.prefix = strings.TrimSpace(query[:clausesStart])
+	q.from = strings.TrimSpace(query[words[from].start:clausesStart])
+	if conditionStart >= 0 {
+		q.condition = strings.TrimSpace(query[conditionStart:conditionEnd])
+	}
+	if conditionEnd < forStart {
+		q.with = " " + strings.TrimSpace(query[conditionEnd:forStart])
+	}
+	if conditionEnd < len(query) {
+		q.suffix = " " + strings.TrimSpace(query[conditionEnd:])
+	}
+	return q, nil
+}
+
+// withIdRange returns the query limited to the Ids from from up to but excluding to,
+// where an empty Id leaves the range open
+func (q chunkableQuery) withIdRange(from string, to string) string {
+	var conditions []string
+	if q.condition != "" {
+		conditions = append(conditions, "("+q.condition+")")
+	}
+	if from != "" {
+		conditions = append(conditions, "Id >= '"+from+"'")
+	}
+	if to != "" {
+		conditions = append(conditions, "Id < '"+to+"'")
+	}
+	if len(conditions) == 0 {
+		return q.prefix + q.suffix
+	}
+	return q.prefix + " WHERE " + strings.Join(conditions, " AND ") + q.suffix
+}
+
+// restQuery returns a REST API query of selectClause over the same records as q. FOR clauses are left out,
+// as they do not change which records are returned.
+func (q chunkableQuery) restQuery(selectClause string, orderBy string) string {
+	query := "SELECT " + selectClause + " " + q.from
+	if q.condition != "" {
+		query += " WHERE " + q.condition
+	}
+	query += q.with
+	if orderBy != "" {
+		query += " ORDER BY " + orderBy
+	}
+	return query
+}
+
+// pkChunkQueries splits query into queries of chunkSize records each, in Id order.
+// The chunk boundaries are read from a REST API query of the Ids in order, so chunks stay even
+// however the Ids are distributed, at the cost of one request per 2000 records.
+func pkChunkQueries(
+	ctx context.Context,
+	sf *Salesforce,
+	query string,
+	options bulkJobOptions,
+) ([]string, error) {
+	q, err := parseChunkableQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	resource := queryResource
+	if options.request.Operation == queryAllOperation {
+		resource = queryAllResource
+	}
+
+	var boundaries []string
+	ids := newQueryIterator(ctx, sf, resource, q.restQuery("Id", "Id"))
+	for read := 0; ids.Next(); {
+		if ids.TotalSize <= options.chunkSize {
+			return []string{query}, nil
+		}
+		for _, record := range ids.records {
+			if read > 0 && read%options.chunkSize == 0 {
+				id, _ := record["Id"].(string)
+				if len(id) < idLength {
+					return nil, fmt.Errorf("invalid record Id %q", id)
+				}
+				boundaries = append(boundaries, id[:idLength])
+			}
+			read++
+		}
+	}
+	if err := ids.Error(); err != nil {
+		return nil, err
+	}
+
+	queries := make([]string, 0, len(boundaries)+1)
+	from := ""
+	for _, to := range boundaries {
+		queries = append(queries, q.withIdRange(from, to))
+		from = to
+	}
+	return append(queries, q.withIdRange(from, "")), nil
+}
+
+// startPKChunkJobs runs a query job for every chunk, with at most parallelJobs running at a time
+func startPKChunkJobs(
+	ctx context.Context,
+	sf *Salesforce,
+	queries []string,
+	options bulkJobOptions,
+) []*bulkQueryChunk {
+	running := make(chan struct{}, max(options.parallelJobs, 1))
+	chunks := make([]*bulkQueryChunk, len(queries))
+	for i, query := range queries {
+		chunk := &bulkQueryChunk{query: query, done: make(chan struct{})}
+		chunks[i] = chunk
+		go func() {
+			defer close(chunk.done)
+			select {
+			case running <- struct{}{}:
+			case <-ctx.Done():
+				chunk.err = ctx.Err()
+				return
+			}
+			defer func() { <-running }()
+			chunk.jobId, chunk.err = runPKChunkJob(ctx, sf, query, options)
+		}()
+	}
+	return chunks
+}
+
+// runPKChunkJob runs the query job of a chunk, aborting and running it again when it fails
+func runPKChunkJob(
+	ctx context.Context,
+	sf *Salesforce,
+	query string,
+	options bulkJobOptions,
+) (string, error) {
+	var err error
+	for attempt := 0; attempt <= pkChunkRetries && ctx.Err() == nil; attempt++ {
+		var job bulkJob
+		job, err = createBulkQueryJob(ctx, sf, query, options)
+		if err != nil {
+			continue
+		}
+		err = waitForJobResults(ctx, sf, job.Id, queryJobType, sf.config.bulkPollInterval)
+		if err == nil {
+			return job.Id, nil
+		}
+		abort, _ := json.Marshal(map[string]string{"state": jobStateAborted})
+		_, _ = doBulkJobRequest( // stop a job that timed out before it is run again
+			context.WithoutCancel(ctx),
+			sf,
+			http.MethodPatch,
+			BulkQueryJob,
+			job.Id,
+			string(abort),
+		)
+	}
+	return "", err
+}
@@ -0,0 +1,457 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func Test_parseChunkableQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "without_where",
+			query: "SELECT Id, Name FROM Account",
+			want:  "SELECT Id, Name FROM Account WHERE Id >= 'a' AND Id < 'b'",
+		},
+		{
+			name:  "with_where_and_trailing_clause",
+			query: "SELECT Id FROM Contact WHERE Name = 'a' OR Name = 'b' WITH SECURITY_ENFORCED",
+			want:  "SELECT Id FROM Contact WHERE (Name = 'a' OR Name = 'b') AND Id >= 'a' AND Id < 'b' WITH SECURITY_ENFORCED",
+		},
+		{
+			name:  "subquery_and_quoted_keywords",
+			query: "SELECT Id, (SELECT Id FROM Contacts ORDER BY Name LIMIT 5) FROM Account where Name = 'for \\' limit'",
+			want:  "SELECT Id, (SELECT Id FROM Contacts ORDER BY Name LIMIT 5) FROM Account WHERE (Name = 'for \\' limit') AND Id >= 'a' AND Id < 'b'",
+		},
+		{
+			name:  "using_scope",
+			query: "SELECT Id FROM Task USING SCOPE mine",
+			want:  "SELECT Id FROM Task USING SCOPE mine WHERE Id >= 'a' AND Id < 'b'",
+		},
+		{
+			name:    "limit",
+			query:   "SELECT Id FROM Account LIMIT 10",
+			wantErr: true,
+		},
+		{
+			name:    "order_by",
+			query:   "SELECT Id FROM Account WHERE Name != null ORDER BY Name",
+			wantErr: true,
+		},
+		{
+			name:    "no_from",
+			query:   "SELECT Id",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseChunkableQuery(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseChunkableQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if chunk := got.withIdRange("a", "b"); chunk != tt.want {
+				t.Errorf("chunkableQuery.withIdRange() = %q, want %q", chunk, tt.want)
+			}
+		})
+	}
+}
+
+func Test_chunkableQuery_restQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "condition",
+			query: "SELECT Id, Name FROM Account WHERE Name != null",
+			want:  "SELECT Id FROM Account WHERE Name != null ORDER BY Id",
+		},
+		{
+			name:  "using_scope_and_with",
+			query: "SELECT Id FROM Task USING SCOPE mine WHERE IsClosed = true WITH SECURITY_ENFORCED",
+			want:  "SELECT Id FROM Task USING SCOPE mine WHERE IsClosed = true WITH SECURITY_ENFORCED ORDER BY Id",
+		},
+		{
+			name:  "with_and_for",
+			query: "SELECT Id FROM Account WITH USER_MODE FOR VIEW",
+			want:  "SELECT Id FROM Account WITH USER_MODE ORDER BY Id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := parseChunkableQuery(tt.query)
+			if err != nil {
+				t.Fatalf("parseChunkableQuery() error = %v", err)
+			}
+			if got := q.restQuery("Id", "Id"); got != tt.want {
+				t.Errorf("chunkableQuery.restQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// setupPKChunkingServer fakes REST queries of ids in pages of 2 records and query jobs whose results
+// contain the lower Id bound of the chunk, or "first" without one
+func setupPKChunkingServer(
+	t *testing.T,
+	ids ...string,
+) (*httptest.Server, authentication, func() []string) {
+	if len(ids) == 0 {
+		ids = []string{"001000000000001AAA", "001000000000002AAA", "00100000000zzzzAAA"}
+	}
+	var mu sync.Mutex
+	var jobQueries []string
+	lowerBound := regexp.MustCompile(`Id >= '(\w+)'`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/services/data/"+apiVersion)
+		var body any
+		switch {
+		case strings.HasPrefix(path, "/query"):
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			resp := queryResponse{TotalSize: len(ids), Done: true}
+			for _, id := range ids[page*2 : min(page*2+2, len(ids))] {
+				resp.Records = append(resp.Records, map[string]any{"Id": id})
+			}
+			if page*2+2 < len(ids) {
+				resp.Done = false
+				resp.NextRecordsUrl = "/services/data/" + apiVersion + "/query?page=" + strconv.Itoa(
+					page+1,
+				)
+			}
+			body = resp
+		case path == "/jobs/query" && r.Method == http.MethodPost:
+			request := bulkQueryJobCreationRequest{}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Fatal(err.Error())
+			}
+			mu.Lock()
+			jobQueries = append(jobQueries, request.Query)
+			mu.Unlock()
+			id := "first"
+			if match := lowerBound.FindStringSubmatch(request.Query); match != nil {
+				id = match[1]
+			}
+			body = bulkJob{Id: id, State: jobStateUploadComplete}
+		case strings.HasSuffix(path, "/results"):
+			id := strings.Split(path, "/")[3]
+			w.Header().Add("Sforce-Locator", "null")
+			if _, err := w.Write([]byte("\"Id\"\n\"" + id + "\"\n")); err != nil {
+				t.Fatal(err.Error())
+			}
+			return
+		default:
+			body = BulkJobResults{Id: strings.Split(path, "/")[3], State: jobStateJobComplete}
+		}
+		data, _ := json.Marshal(body)
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+	return server, sfAuth, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), jobQueries...)
+	}
+}
+
+func Test_pkChunkQueries(t *testing.T) {
+	server, sfAuth, _ := setupPKChunkingServer(t)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	t.Run("split_by_chunk_size", func(t *testing.T) {
+		got, err := pkChunkQueries(
+			context.Background(),
+			sf,
+			"SELECT Id FROM Account WHERE Name != null",
+			bulkJobOptions{chunkSize: 1, parallelJobs: 2},
+		)
+		if err != nil {
+			t.Fatalf("pkChunkQueries() error = %v", err)
+		}
+		want := []string{
+			"SELECT Id FROM Account WHERE (Name != null) AND Id < '001000000000002'",
+			"SELECT Id FROM Account WHERE (Name != null) AND Id >= '001000000000002' AND Id < '00100000000zzzz'",
+			"SELECT Id FROM Account WHERE (Name != null) AND Id >= '00100000000zzzz'",
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("pkChunkQueries() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("skewed_ids", func(t *testing.T) {
+		skewed, skewedAuth, _ := setupPKChunkingServer(
+			t,
+			"001000000000001AAA",
+			"001000000000002AAA",
+			"001000000000003AAA",
+			"001000000000004AAA",
+			"0010000000000zyAAA",
+			"0010000000000zzAAA",
+			"00100000000zzzzAAA",
+		)
+		defer skewed.Close()
+		got, err := pkChunkQueries(
+			context.Background(),
+			buildSalesforceStruct(&skewedAuth),
+			"SELECT Id FROM Account",
+			bulkJobOptions{chunkSize: 3, parallelJobs: 2},
+		)
+		if err != nil {
+			t.Fatalf("pkChunkQueries() error = %v", err)
+		}
+		want := []string{
+			"SELECT Id FROM Account WHERE Id < '001000000000004'",
+			"SELECT Id FROM Account WHERE Id >= '001000000000004' AND Id < '00100000000zzzz'",
+			"SELECT Id FROM Account WHERE Id >= '00100000000zzzz'",
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("pkChunkQueries() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("single_chunk", func(t *testing.T) {
+		got, err := pkChunkQueries(
+			context.Background(),
+			sf,
+			"SELECT Id FROM Account",
+			bulkJobOptions{chunkSize: 10, parallelJobs: 2},
+		)
+		if err != nil {
+			t.Fatalf("pkChunkQueries() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, []string{"SELECT Id FROM Account"}) {
+			t.Errorf("pkChunkQueries() = %q, want the query unchanged", got)
+		}
+	})
+
+	t.Run("unsupported_query", func(t *testing.T) {
+		_, err := pkChunkQueries(
+			context.Background(),
+			sf,
+			"SELECT Id FROM Account LIMIT 5",
+			bulkJobOptions{chunkSize: 1, parallelJobs: 2},
+		)
+		if err == nil {
+			t.Error("pkChunkQueries() expected error")
+		}
+	})
+}
+
+func Test_runPKChunkJob(t *testing.T) {
+	var mu sync.Mutex
+	var created, aborted int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var body any
+		switch r.Method {
+		case http.MethodPost:
+			created++
+			body = bulkJob{Id: "job" + strconv.Itoa(created), State: jobStateUploadComplete}
+		case http.MethodPatch:
+			aborted++
+			body = BulkJobInfo{State: jobStateAborted}
+		default:
+			job := BulkJobResults{Id: "job", State: jobStateJobComplete}
+			if strings.HasSuffix(r.URL.Path, "/job1") {
+				job.State = jobStateFailed
+				job.ErrorMessage = "QUERY_TIMEOUT"
+			}
+			body = job
+		}
+		data, _ := json.Marshal(body)
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	t.Run("retry_failed_job", func(t *testing.T) {
+		got, err := runPKChunkJob(
+			context.Background(),
+			buildSalesforceStruct(&sfAuth),
+			"SELECT Id FROM Account",
+			bulkJobOptions{request: bulkQueryJobCreationRequest{Operation: queryJobType}},
+		)
+		if err != nil {
+			t.Fatalf("runPKChunkJob() error = %v", err)
+		}
+		if got != "job2" || created != 2 || aborted != 1 {
+			t.Errorf(
+				"runPKChunkJob() = %v after %d jobs and %d aborts, want job2, 2 and 1",
+				got,
+				created,
+				aborted,
+			)
+		}
+	})
+
+	t.Run("give_up", func(t *testing.T) {
+		_, err := runPKChunkJob(
+			context.Background(),
+			buildSalesforceStruct(&badSfAuth),
+			"SELECT Id FROM Account",
+			bulkJobOptions{request: bulkQueryJobCreationRequest{Operation: queryJobType}},
+		)
+		if err == nil {
+			t.Error("runPKChunkJob() expected error")
+		}
+	})
+}
+
+func Test_doQueryBulkTo_pkChunking(t *testing.T) {
+	server, sfAuth, jobQueries := setupPKChunkingServer(t)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	t.Run("merge_chunks_in_order", func(t *testing.T) {
+		var buf strings.Builder
+		err := doQueryBulkTo(
+			context.Background(),
+			sf,
+			&buf,
+			"SELECT Id FROM Account",
+			WithPKChunking(1, 3),
+		)
+		if err != nil {
+			t.Fatalf("doQueryBulkTo() error = %v", err)
+		}
+		want := "Id\nfirst\n001000000000002\n00100000000zzzz\n"
+		if buf.String() != want {
+			t.Errorf("doQueryBulkTo() wrote %q, want %q", buf.String(), want)
+		}
+		if len(jobQueries()) != 3 {
+			t.Errorf("doQueryBulkTo() created %d jobs, want 3", len(jobQueries()))
+		}
+	})
+
+	t.Run("iterate_chunks_in_order", func(t *testing.T) {
+		it, err := sf.QueryBulkIterator("SELECT Id FROM Account", WithPKChunking(1, 2))
+		if err != nil {
+			t.Fatalf("Salesforce.QueryBulkIterator() error = %v", err)
+		}
+		var got []string
+		for it.Next() {
+			var rows []struct{ Id string }
+			if err := it.Decode(&rows); err != nil {
+				t.Fatalf("IteratorJob.Decode() error = %v", err)
+			}
+			for _, row := range rows {
+				got = append(got, row.Id)
+			}
+			if it.JobId() != "" || it.Locator() != "" {
+				t.Errorf(
+					"ResumableIterator position = %q, %q, want none with PK chunking",
+					it.JobId(),
+					it.Locator(),
+				)
+			}
+		}
+		if err := it.Error(); err != nil {
+			t.Fatalf("IteratorJob.Error() = %v", err)
+		}
+		want := []string{"first", "001000000000002", "00100000000zzzz"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("IteratorJob rows = %v, want %v", got, want)
+		}
+	})
+}
+
+func Test_bulkJobQueryIterator_Close(t *testing.T) {
+	var mu sync.Mutex
+	var aborted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/services/data/"+apiVersion)
+		var body any
+		switch {
+		case strings.HasPrefix(path, "/query"):
+			body = queryResponse{
+				TotalSize: 2,
+				Done:      true,
+				Records: []map[string]any{
+					{"Id": "001000000000001AAA"},
+					{"Id": "001000000000009AAA"},
+				},
+			}
+		case path == "/jobs/query" && r.Method == http.MethodPost:
+			request := bulkQueryJobCreationRequest{}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Fatal(err.Error())
+			}
+			id := "first"
+			if strings.Contains(request.Query, "Id >=") {
+				id = "second"
+			}
+			body = bulkJob{Id: id, State: jobStateUploadComplete}
+		case r.Method == http.MethodPatch:
+			mu.Lock()
+			aborted = append(aborted, strings.Split(path, "/")[3])
+			mu.Unlock()
+			body = BulkJobInfo{State: jobStateAborted}
+		case strings.HasSuffix(path, "/results"):
+			w.Header().Add("Sforce-Locator", "null")
+			if _, err := w.Write([]byte("\"Id\"\n\"first\"\n")); err != nil {
+				t.Fatal(err.Error())
+			}
+			return
+		default:
+			id := strings.Split(path, "/")[3]
+			job := BulkJobResults{Id: id, State: jobStateJobComplete}
+			if id == "second" {
+				job.State = "InProgress" // never completes
+			}
+			body = job
+		}
+		data, _ := json.Marshal(body)
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	it, err := buildSalesforceStruct(&sfAuth).QueryBulkIterator(
+		"SELECT Id FROM Account",
+		WithPKChunking(1, 2),
+	)
+	if err != nil {
+		t.Fatalf("Salesforce.QueryBulkIterator() error = %v", err)
+	}
+	if !it.Next() {
+		t.Fatalf("ResumableIterator.Next() = false, error = %v", it.Error())
+	}
+	if err := it.Close(); err != nil {
+		t.Fatalf("ResumableIterator.Close() error = %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(aborted, []string{"second"}) {
+		t.Errorf("ResumableIterator.Close() aborted %v, want the running job", aborted)
+	}
+	if it.Next() {
+		t.Error("ResumableIterator.Next() = true after Close")
+	}
+}
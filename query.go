@@ -1,8 +1,12 @@
 package salesforce
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"iter"
 	"net/http"
 	"net/url"
 	"strings"
@@ -15,50 +19,190 @@ type queryResponse struct {
 	Records        []map[string]any `json:"records"`
 }
 
-func performQuery(sf *Salesforce, query string, sObject any) error {
-	query = url.QueryEscape(query)
-	queryResp := &queryResponse{
-		Done:           false,
-		NextRecordsUrl: "/query/?q=" + query,
-	}
-
-	for !queryResp.Done {
-		resp, err := doRequest(sf.auth, sf.config, requestPayload{
-			method:   http.MethodGet,
-			uri:      queryResp.NextRecordsUrl,
-			content:  jsonType,
-			compress: sf.config.compressionHeaders,
-		})
-		if err != nil {
-			return err
-		}
-
-		respBody, readErr := io.ReadAll(resp.Body)
-		if readErr != nil {
-			return readErr
-		}
-
-		tempQueryResp := &queryResponse{}
-		queryResponseError := json.Unmarshal(respBody, &tempQueryResp)
-		if queryResponseError != nil {
-			return queryResponseError
-		}
+const (
+	queryResource    = "/query/"
+	queryAllResource = "/queryAll/" // includes deleted and archived records
+)
 
-		queryResp.TotalSize = queryResp.TotalSize + tempQueryResp.TotalSize
-		queryResp.Records = append(queryResp.Records, tempQueryResp.Records...)
-		queryResp.Done = tempQueryResp.Done
-		if !tempQueryResp.Done && tempQueryResp.NextRecordsUrl != "" {
-			queryResp.NextRecordsUrl = strings.TrimPrefix(
-				tempQueryResp.NextRecordsUrl,
-				"/services/data/"+apiVersion,
-			)
-		}
+func performQuery(
+	ctx context.Context,
+	sf *Salesforce,
+	resource string,
+	query string,
+	sObject any,
+) error {
+	it := newQueryIterator(ctx, sf, resource, query)
+	var records []map[string]any
+	for it.Next() {
+		records = append(records, it.records...)
+	}
+	if err := it.Error(); err != nil {
+		return err
 	}
 
-	sObjectError := mapstructureDecode(queryResp.Records, sObject)
+	sObjectError := mapstructureDecode(records, sObject)
 	if sObjectError != nil {
 		return sObjectError
 	}
 
 	return nil
 }
+
+// QueryIterator fetches the results of a REST API query one page at a time.
+// Each call to Next requests the following page, so only the current page is held in memory
+// and a loop can stop early without fetching the remaining pages.
+type QueryIterator struct {
+	TotalSize int // total number of records matched by the query, set by the first call to Next
+	nextUri   string
+	records   []map[string]any
+	err       error
+	auth      *authentication
+	config    *configuration
+	ctx       context.Context
+}
+
+func newQueryIterator(
+	ctx context.Context,
+	sf *Salesforce,
+	resource string,
+	query string,
+) *QueryIterator {
+	return &QueryIterator{
+		nextUri: resource + "?q=" + url.QueryEscape(query),
+		auth:    sf.auth,
+		config:  sf.config,
+		ctx:     ctx,
+	}
+}
+
+// Next fetches the next page of records, returning false when there are no more pages or a request failed.
+func (it *QueryIterator) Next() bool {
+	it.records = nil
+	if it.nextUri == "" || it.err != nil {
+		return false
+	}
+	resp, err := doRequest(it.ctx, it.auth, it.config, requestPayload{
+		method:   http.MethodGet,
+		uri:      it.nextUri,
+		content:  jsonType,
+		compress: it.config.compressionHeaders,
+	})
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	respBody, readErr := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if readErr != nil {
+		it.err = readErr
+		return false
+	}
+
+	queryResp := &queryResponse{}
+	if err := json.Unmarshal(respBody, &queryResp); err != nil {
+		it.err = err
+		return false
+	}
+
+	it.TotalSize = queryResp.TotalSize
+	it.records = queryResp.Records
+	if !queryResp.Done && queryResp.NextRecordsUrl != "" {
+		it.nextUri = strings.TrimPrefix(queryResp.NextRecordsUrl, it.config.dataPath())
+	} else {
+		it.nextUri = ""
+	}
+	return true
+}
+
+// Decode decodes the records of the current page into val, which should be a pointer to a slice.
+func (it *QueryIterator) Decode(val any) error {
+	return mapstructureDecode(it.records, val)
+}
+
+// Error returns the error that stopped the iteration, if any.
+func (it *QueryIterator) Error() error {
+	return it.err
+}
+
+// ErrNoRecords is returned by QueryOne when the query matches no records.
+var ErrNoRecords = errors.New("query returned no records")
+
+// QueryAs performs a SOQL query and decodes the records into a slice of T.
+func QueryAs[T any](sf *Salesforce, query string) ([]T, error) {
+	return QueryAsCtx[T](context.Background(), sf, query)
+}
+
+// QueryAsCtx is like QueryAs but uses ctx for the underlying HTTP requests.
+func QueryAsCtx[T any](ctx context.Context, sf *Salesforce, query string) ([]T, error) {
+	if err := validateAuth(*sf); err != nil {
+		return nil, err
+	}
+	records := []T{}
+	if err := performQuery(ctx, sf, queryResource, query, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// QueryOne performs a SOQL query that is expected to match exactly one record and decodes it into T.
+// ErrNoRecords is returned when nothing matches.
+func QueryOne[T any](sf *Salesforce, query string) (T, error) {
+	return QueryOneCtx[T](context.Background(), sf, query)
+}
+
+// QueryOneCtx is like QueryOne but uses ctx for the underlying HTTP requests.
+func QueryOneCtx[T any](ctx context.Context, sf *Salesforce, query string) (T, error) {
+	var record T
+	if err := validateAuth(*sf); err != nil {
+		return record, err
+	}
+	it := newQueryIterator(ctx, sf, queryResource, query)
+	if !it.Next() {
+		return record, it.Error()
+	}
+	if it.TotalSize == 0 || len(it.records) == 0 {
+		return record, ErrNoRecords
+	}
+	if it.TotalSize > 1 {
+		return record, fmt.Errorf("query returned %d records, expected one", it.TotalSize)
+	}
+	if err := mapstructureDecode(it.records[0], &record); err != nil {
+		return record, err
+	}
+	return record, nil
+}
+
+// QuerySeq performs a SOQL query and returns an iterator over the records decoded into T,
+// for use with range-over-func. Pages are fetched as the loop advances, like QueryIterator,
+// and breaking out of the loop stops fetching. An error ends the sequence.
+func QuerySeq[T any](sf *Salesforce, query string) iter.Seq2[T, error] {
+	return QuerySeqCtx[T](context.Background(), sf, query)
+}
+
+// QuerySeqCtx is like QuerySeq but uses ctx for the underlying HTTP requests.
+func QuerySeqCtx[T any](ctx context.Context, sf *Salesforce, query string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		if err := validateAuth(*sf); err != nil {
+			yield(zero, err)
+			return
+		}
+		it := newQueryIterator(ctx, sf, queryResource, query)
+		for it.Next() {
+			var page []T
+			if err := it.Decode(&page); err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, record := range page {
+				if !yield(record, nil) {
+					return
+				}
+			}
+		}
+		if err := it.Error(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
@@ -1,11 +1,16 @@
 package salesforce
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 )
 
@@ -113,7 +118,7 @@ func Test_performQuery(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := performQuery(tt.args.sf, tt.args.query, &tt.args.sObject); (err != nil) != tt.wantErr {
+			if err := performQuery(context.Background(), tt.args.sf, queryResource, tt.args.query, &tt.args.sObject); (err != nil) != tt.wantErr {
 				t.Errorf("performQuery() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			if !reflect.DeepEqual(tt.args.sObject, tt.want) {
@@ -122,3 +127,280 @@ func Test_performQuery(t *testing.T) {
 		})
 	}
 }
+
+func setupPagedQueryServer(
+	pages int,
+	pageSize int,
+) (*httptest.Server, authentication, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := int(requests.Add(1))
+		resp := queryResponse{
+			TotalSize: pages * pageSize,
+			Done:      page >= pages,
+		}
+		if !resp.Done {
+			resp.NextRecordsUrl = fmt.Sprintf(
+				"/services/data/%s/query/01g-%d",
+				apiVersion,
+				page*pageSize,
+			)
+		}
+		for i := range pageSize {
+			resp.Records = append(resp.Records, map[string]any{
+				"Id": strconv.Itoa((page-1)*pageSize + i),
+			})
+		}
+		body, _ := json.Marshal(resp)
+		if _, err := w.Write(body); err != nil {
+			panic(err.Error())
+		}
+	}))
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstoken",
+	}
+	return server, sfAuth, &requests
+}
+
+func TestQueryIterator(t *testing.T) {
+	type account struct {
+		Id string
+	}
+	var _ IteratorJob = &QueryIterator{}
+
+	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	tests := []struct {
+		name         string
+		pages        int
+		stopAfter    int
+		wantRecords  int
+		wantRequests int32
+		wantErr      bool
+	}{
+		{
+			name:         "all_pages",
+			pages:        3,
+			stopAfter:    0,
+			wantRecords:  6,
+			wantRequests: 3,
+			wantErr:      false,
+		},
+		{
+			name:         "stop_early",
+			pages:        3,
+			stopAfter:    1,
+			wantRecords:  2,
+			wantRequests: 1,
+			wantErr:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, sfAuth, requests := setupPagedQueryServer(tt.pages, 2)
+			defer server.Close()
+			sf := buildSalesforceStruct(&sfAuth)
+
+			it, err := sf.QueryIterator("SELECT Id FROM Account")
+			if err != nil {
+				t.Fatalf("Salesforce.QueryIterator() error = %v", err)
+			}
+			var got []account
+			for pages := 1; it.Next(); pages++ {
+				var page []account
+				if err := it.Decode(&page); err != nil {
+					t.Fatalf("QueryIterator.Decode() error = %v", err)
+				}
+				got = append(got, page...)
+				if pages == tt.stopAfter {
+					break
+				}
+			}
+			if (it.Error() != nil) != tt.wantErr {
+				t.Errorf("QueryIterator.Error() = %v, wantErr %v", it.Error(), tt.wantErr)
+			}
+			if len(got) != tt.wantRecords {
+				t.Errorf("QueryIterator records = %v, want %v", len(got), tt.wantRecords)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("QueryIterator requests = %v, want %v", got, tt.wantRequests)
+			}
+			if it.TotalSize != tt.pages*2 {
+				t.Errorf("QueryIterator.TotalSize = %v, want %v", it.TotalSize, tt.pages*2)
+			}
+		})
+	}
+
+	t.Run("http_error", func(t *testing.T) {
+		sf := buildSalesforceStruct(&badSfAuth)
+		it, err := sf.QueryIterator("SELECT Id FROM Account")
+		if err != nil {
+			t.Fatalf("Salesforce.QueryIterator() error = %v", err)
+		}
+		if it.Next() {
+			t.Error("QueryIterator.Next() = true, want false")
+		}
+		if it.Error() == nil {
+			t.Error("QueryIterator.Error() = nil, want error")
+		}
+	})
+
+	t.Run("validation_fail", func(t *testing.T) {
+		sf := buildSalesforceStruct(nil)
+		if _, err := sf.QueryIterator("SELECT Id FROM Account"); err == nil {
+			t.Error("Salesforce.QueryIterator() expected validation error")
+		}
+	})
+}
+
+func TestQueryAs(t *testing.T) {
+	type account struct {
+		Id string
+	}
+	server, sfAuth, _ := setupPagedQueryServer(2, 2)
+	defer server.Close()
+
+	got, err := QueryAs[account](buildSalesforceStruct(&sfAuth), "SELECT Id FROM Account")
+	if err != nil {
+		t.Fatalf("QueryAs() error = %v", err)
+	}
+	want := []account{{Id: "0"}, {Id: "1"}, {Id: "2"}, {Id: "3"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryAs() = %v, want %v", got, want)
+	}
+
+	if _, err := QueryAs[account](buildSalesforceStruct(nil), "SELECT Id FROM Account"); err == nil {
+		t.Error("QueryAs() expected validation error")
+	}
+}
+
+func TestQueryOne(t *testing.T) {
+	type account struct {
+		Id   string
+		Name string `salesforce:"Account_Name__c"`
+	}
+	oneServer, oneSfAuth := setupTestServer(queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records:   []map[string]any{{"Id": "123abc", "Account_Name__c": "test account"}},
+	}, http.StatusOK)
+	defer oneServer.Close()
+
+	noneServer, noneSfAuth := setupTestServer(queryResponse{
+		TotalSize: 0,
+		Done:      true,
+		Records:   []map[string]any{},
+	}, http.StatusOK)
+	defer noneServer.Close()
+
+	manyServer, manySfAuth, _ := setupPagedQueryServer(1, 2)
+	defer manyServer.Close()
+
+	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	tests := []struct {
+		name      string
+		auth      *authentication
+		want      account
+		wantErr   bool
+		wantNoRec bool
+	}{
+		{
+			name:    "one_record",
+			auth:    &oneSfAuth,
+			want:    account{Id: "123abc", Name: "test account"},
+			wantErr: false,
+		},
+		{
+			name:      "no_records",
+			auth:      &noneSfAuth,
+			want:      account{},
+			wantErr:   true,
+			wantNoRec: true,
+		},
+		{
+			name:    "many_records",
+			auth:    &manySfAuth,
+			want:    account{},
+			wantErr: true,
+		},
+		{
+			name:    "http_error",
+			auth:    &badSfAuth,
+			want:    account{},
+			wantErr: true,
+		},
+		{
+			name:    "validation_fail",
+			auth:    nil,
+			want:    account{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := QueryOne[account](buildSalesforceStruct(tt.auth), "SELECT Id FROM Account")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QueryOne() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrNoRecords) != tt.wantNoRec {
+				t.Errorf("QueryOne() error = %v, want ErrNoRecords %v", err, tt.wantNoRec)
+			}
+			if got != tt.want {
+				t.Errorf("QueryOne() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuerySeq(t *testing.T) {
+	type account struct {
+		Id string
+	}
+
+	t.Run("all_records", func(t *testing.T) {
+		server, sfAuth, _ := setupPagedQueryServer(2, 2)
+		defer server.Close()
+		var got []string
+		for record, err := range QuerySeq[account](buildSalesforceStruct(&sfAuth), "SELECT Id FROM Account") {
+			if err != nil {
+				t.Fatalf("QuerySeq() error = %v", err)
+			}
+			got = append(got, record.Id)
+		}
+		if want := []string{"0", "1", "2", "3"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("QuerySeq() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("break_stops_fetching", func(t *testing.T) {
+		server, sfAuth, requests := setupPagedQueryServer(3, 2)
+		defer server.Close()
+		for record, err := range QuerySeq[account](buildSalesforceStruct(&sfAuth), "SELECT Id FROM Account") {
+			if err != nil {
+				t.Fatalf("QuerySeq() error = %v", err)
+			}
+			if record.Id == "0" {
+				break
+			}
+		}
+		if got := requests.Load(); got != 1 {
+			t.Errorf("QuerySeq() requests = %v, want 1", got)
+		}
+	})
+
+	t.Run("http_error", func(t *testing.T) {
+		server, sfAuth := setupTestServer("", http.StatusBadRequest)
+		defer server.Close()
+		var gotErr error
+		for _, err := range QuerySeq[account](buildSalesforceStruct(&sfAuth), "SELECT Id FROM Account") {
+			gotErr = err
+		}
+		if gotErr == nil {
+			t.Error("QuerySeq() expected error")
+		}
+	})
+}
@@ -3,8 +3,8 @@ package salesforce
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
-	"errors"
 	"io"
 	"net/http"
 	"strings"
@@ -25,6 +25,7 @@ type requestPayload struct {
 	uri          string
 	content      string
 	body         string
+	bodyReader   io.Reader // streamed instead of body, so the request cannot be sent again
 	retry        bool
 	compress     bool
 	options      []RequestOption
@@ -32,6 +33,47 @@ type requestPayload struct {
 }
 
 func doRequest(
+	ctx context.Context,
+	auth *authentication,
+	config *configuration,
+	payload requestPayload,
+) (*http.Response, error) {
+	var resp *http.Response
+	var err error
+	for attempt := 1; ; attempt++ {
+		resp, err = sendRequest(ctx, auth, config, payload)
+		wait, retry := config.retryPolicy.shouldRetry(ctx, payload.method, attempt, resp, err)
+		if !retry || payload.bodyReader != nil {
+			break
+		}
+		if sleepErr := sleepContext(ctx, wait); sleepErr != nil {
+			break // report the outcome of the last attempt
+		}
+		if resp != nil {
+			_ = resp.Body.Close() // the response is discarded in favour of the next attempt
+		}
+	}
+	if err != nil {
+		return resp, err
+	}
+	if (resp.StatusCode < 200 || resp.StatusCode > 300) &&
+		resp.StatusCode != http.StatusNotModified { // conditional requests sent with If-Modified-Since
+		resp, err = processSalesforceError(ctx, *resp, auth, config, payload)
+		if err != nil {
+			return resp, err
+		}
+	}
+
+	// salesforce does not guarantee that the response will be compressed
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		resp.Body, err = decompress(resp.Body)
+	}
+
+	return resp, err
+}
+
+func sendRequest(
+	ctx context.Context,
 	auth *authentication,
 	config *configuration,
 	payload requestPayload,
@@ -39,13 +81,26 @@ func doRequest(
 	var reader io.Reader
 	var req *http.Request
 	var err error
-	base := "/services/data/" + config.apiVersion
+	base := config.dataPath()
 	if payload.endpointBase != "" {
 		base = payload.endpointBase
 	}
-	endpoint := auth.InstanceUrl + base + payload.uri
+	instanceUrl, accessToken := auth.endpoint()
+	endpoint := instanceUrl + base + payload.uri
 
-	if payload.body != "" {
+	if payload.bodyReader != nil {
+		reader = payload.bodyReader
+		if payload.compress {
+			compressed := compressStream(payload.bodyReader)
+			reader = compressed
+			defer func() {
+				if err != nil {
+					_ = compressed.Close() // the request was not sent, so stop compressing
+				}
+			}()
+		}
+		req, err = http.NewRequestWithContext(ctx, payload.method, endpoint, reader)
+	} else if payload.body != "" {
 		if payload.compress {
 			reader, err = compress(payload.body)
 			if err != nil {
@@ -54,9 +109,9 @@ func doRequest(
 		} else {
 			reader = strings.NewReader(payload.body)
 		}
-		req, err = http.NewRequest(payload.method, endpoint, reader)
+		req, err = http.NewRequestWithContext(ctx, payload.method, endpoint, reader)
 	} else {
-		req, err = http.NewRequest(payload.method, endpoint, nil)
+		req, err = http.NewRequestWithContext(ctx, payload.method, endpoint, nil)
 	}
 	if err != nil {
 		return nil, err
@@ -65,7 +120,7 @@ func doRequest(
 	req.Header.Set("User-Agent", "go-salesforce")
 	req.Header.Set("Content-Type", payload.content)
 	req.Header.Set("Accept", payload.content)
-	req.Header.Set("Authorization", "Bearer "+auth.AccessToken)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
 	if payload.compress {
 		req.Header.Set("Content-Encoding", "gzip") // compress request
 		req.Header.Set("Accept-Encoding", "gzip")  // compress response
@@ -76,23 +131,7 @@ func doRequest(
 		option(req)
 	}
 
-	resp, err := config.httpClient.Do(req)
-	if err != nil {
-		return resp, err
-	}
-	if resp.StatusCode < 200 || resp.StatusCode > 300 {
-		resp, err = processSalesforceError(*resp, auth, config, payload)
-		if err != nil {
-			return resp, err
-		}
-	}
-
-	// salesforce does not guarantee that the response will be compressed
-	if resp.Header.Get("Content-Encoding") == "gzip" {
-		resp.Body, err = decompress(resp.Body)
-	}
-
-	return resp, err
+	return config.httpClient.Do(req)
 }
 
 func compress(body string) (io.Reader, error) {
@@ -107,6 +146,20 @@ func compress(body string) (io.Reader, error) {
 	return &buf, nil
 }
 
+// compressStream compresses body while it is being read
+func compressStream(body io.Reader) *io.PipeReader {
+	pr, pw := io.Pipe()
+	go func() {
+		gz := gzip.NewWriter(pw)
+		_, err := io.Copy(gz, body)
+		if err == nil {
+			err = gz.Close()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	return pr
+}
+
 func decompress(body io.ReadCloser) (io.ReadCloser, error) {
 	gzReader, err := gzip.NewReader(body)
 	if err != nil {
@@ -124,35 +177,57 @@ func decompress(body io.ReadCloser) (io.ReadCloser, error) {
 	return io.NopCloser(bytes.NewReader(decompressed)), nil
 }
 
+// parseSalesforceErrors decodes an error response body, which is either a list of errors or a single error
+func parseSalesforceErrors(responseData []byte) []SalesforceErrorMessage {
+	var sfErrors []SalesforceErrorMessage
+	if err := json.Unmarshal(responseData, &sfErrors); err == nil {
+		return sfErrors
+	}
+	var singleError SalesforceErrorMessage
+	if err := json.Unmarshal(responseData, &singleError); err == nil {
+		return []SalesforceErrorMessage{singleError}
+	}
+	return nil
+}
+
+// rejectedToken returns the access token that was sent with the request, if known
+func rejectedToken(resp http.Response) string {
+	if resp.Request == nil {
+		return ""
+	}
+	return strings.TrimPrefix(resp.Request.Header.Get("Authorization"), "Bearer ")
+}
+
 func processSalesforceError(
+	ctx context.Context,
 	resp http.Response,
 	auth *authentication,
 	config *configuration,
 	payload requestPayload,
 ) (*http.Response, error) {
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		body, err := decompress(resp.Body)
+		if err != nil {
+			return &resp, err
+		}
+		resp.Body = body
+	}
 	responseData, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return &resp, err
 	}
-	var sfErrors []SalesforceErrorMessage
-	err = json.Unmarshal(responseData, &sfErrors)
-	if err != nil {
-		var singleError SalesforceErrorMessage
-		if singleErr := json.Unmarshal(responseData, &singleError); singleErr == nil {
-			sfErrors = []SalesforceErrorMessage{singleError}
-		} else {
-			return &resp, errors.New(string(responseData))
-		}
-	}
+	sfErrors := parseSalesforceErrors(responseData)
 	for _, sfError := range sfErrors {
 		if sfError.ErrorCode == invalidSessionIdError &&
-			!payload.retry { // only attempt to refresh the session once
-			err = refreshSession(auth)
+			!payload.retry && // only attempt to refresh the session once
+			payload.bodyReader == nil { // a streamed body has been consumed
+			err = refreshSessionOnce(ctx, auth, config, rejectedToken(resp))
 			if err != nil {
 				return &resp, err
 			}
 
 			newResp, err := doRequest(
+				ctx,
 				auth,
 				config,
 				requestPayload{
@@ -173,5 +248,11 @@ func processSalesforceError(
 		}
 	}
 
-	return &resp, errors.New(string(responseData))
+	return &resp, &APIError{
+		StatusCode: resp.StatusCode,
+		Errors:     sfErrors,
+		Method:     payload.method,
+		URI:        payload.uri,
+		Body:       responseData,
+	}
 }
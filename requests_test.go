@@ -1,6 +1,7 @@
 package salesforce
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -99,7 +100,7 @@ func Test_doRequest(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			config := getDefaultConfig(t)
-			got, err := doRequest(tt.args.auth, config, tt.args.payload)
+			got, err := doRequest(context.Background(), tt.args.auth, config, tt.args.payload)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doRequest() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -111,6 +112,46 @@ func Test_doRequest(t *testing.T) {
 	}
 }
 
+func Test_doRequest_bodyReader(t *testing.T) {
+	var gotBody string
+	var gotEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEncoding = r.Header.Get("Content-Encoding")
+		body := r.Body
+		if gotEncoding == "gzip" {
+			var err error
+			if body, err = decompress(r.Body); err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+		data, _ := io.ReadAll(body)
+		gotBody = string(data)
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	for _, compress := range []bool{false, true} {
+		t.Run("compress_"+strconv.FormatBool(compress), func(t *testing.T) {
+			_, err := doRequest(context.Background(), &sfAuth, getDefaultConfig(t), requestPayload{
+				method:     http.MethodPut,
+				uri:        "/jobs/ingest/750/batches",
+				content:    csvType,
+				bodyReader: strings.NewReader("Name\ntest\n"),
+				compress:   compress,
+			})
+			if err != nil {
+				t.Fatalf("doRequest() error = %v", err)
+			}
+			if gotBody != "Name\ntest\n" {
+				t.Errorf("doRequest() sent body %q", gotBody)
+			}
+			if (gotEncoding == "gzip") != compress {
+				t.Errorf("doRequest() Content-Encoding = %q, compress %v", gotEncoding, compress)
+			}
+		})
+	}
+}
+
 func Test_compression(t *testing.T) {
 	compressedResp, _ := compress("testRecord1")
 
@@ -292,7 +333,13 @@ func Test_processSalesforceError(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			config := getDefaultConfig(t)
-			got, err := processSalesforceError(tt.args.resp, tt.args.auth, config, tt.args.payload)
+			got, err := processSalesforceError(
+				context.Background(),
+				tt.args.resp,
+				tt.args.auth,
+				config,
+				tt.args.payload,
+			)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("processSalesforceError() error = %v, wantErr %v", err, tt.wantErr)
 				return
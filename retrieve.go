@@ -0,0 +1,144 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const retrieveBatchSizeMax = 2000 // ids per sObject Collections retrieve request
+
+type sObjectCollectionRetrieve struct {
+	Ids    []string `json:"ids"`
+	Fields []string `json:"fields"`
+}
+
+func fieldsParam(fields []string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	return "?fields=" + url.QueryEscape(strings.Join(fields, ","))
+}
+
+// doGetRecord gets a single record and decodes it into sObject
+func doGetRecord(ctx context.Context, sf *Salesforce, uri string, sObject any) error {
+	resp, err := doRequest(ctx, sf.auth, sf.config, requestPayload{
+		method:   http.MethodGet,
+		uri:      uri,
+		content:  jsonType,
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return err
+	}
+	respBody, readErr := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if readErr != nil {
+		return readErr
+	}
+
+	// an external id matching several records responds with the urls of the matching records
+	if resp.StatusCode == http.StatusMultipleChoices {
+		return fmt.Errorf("more than one record matches: %w", &APIError{
+			StatusCode: resp.StatusCode,
+			Method:     http.MethodGet,
+			URI:        uri,
+			Body:       respBody,
+		})
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(respBody, &record); err != nil {
+		return err
+	}
+	return mapstructureDecode(record, sObject)
+}
+
+func doGetOne(
+	ctx context.Context,
+	sf *Salesforce,
+	sObjectName string,
+	id string,
+	fields []string,
+	sObject any,
+) error {
+	if sObjectName == "" || id == "" {
+		return errors.New("sObject name and id are required")
+	}
+	uri := "/sobjects/" + sObjectName + "/" + url.PathEscape(id) + fieldsParam(fields)
+	return doGetRecord(ctx, sf, uri, sObject)
+}
+
+func doGetByExternalId(
+	ctx context.Context,
+	sf *Salesforce,
+	sObjectName string,
+	fieldName string,
+	externalId string,
+	fields []string,
+	sObject any,
+) error {
+	if sObjectName == "" || fieldName == "" || externalId == "" {
+		return errors.New("sObject name, external id field and external id are required")
+	}
+	uri := "/sobjects/" + sObjectName + "/" + fieldName + "/" + url.PathEscape(externalId) +
+		fieldsParam(fields)
+	return doGetRecord(ctx, sf, uri, sObject)
+}
+
+// doGetCollection retrieves the records in batches of up to 2000 ids.
+// Records are returned in the order of the ids, with nil for ids that were not found.
+func doGetCollection(
+	ctx context.Context,
+	sf *Salesforce,
+	sObjectName string,
+	ids []string,
+	fields []string,
+	sObjects any,
+) error {
+	if sObjectName == "" {
+		return errors.New("sObject name is required")
+	}
+	if len(fields) == 0 {
+		return errors.New("at least one field is required")
+	}
+
+	records := make([]map[string]any, 0, len(ids))
+	for len(ids) > 0 {
+		batch := ids[:min(len(ids), retrieveBatchSizeMax)]
+		ids = ids[len(batch):]
+
+		body, err := json.Marshal(sObjectCollectionRetrieve{Ids: batch, Fields: fields})
+		if err != nil {
+			return err
+		}
+		resp, err := doRequest(ctx, sf.auth, sf.config, requestPayload{
+			method:   http.MethodPost,
+			uri:      "/composite/sobjects/" + sObjectName,
+			content:  jsonType,
+			body:     string(body),
+			compress: sf.config.compressionHeaders,
+		})
+		if err != nil {
+			return err
+		}
+		respBody, readErr := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if readErr != nil {
+			return readErr
+		}
+
+		var batchRecords []map[string]any
+		if err := json.Unmarshal(respBody, &batchRecords); err != nil {
+			return err
+		}
+		records = append(records, batchRecords...)
+	}
+
+	return mapstructureDecode(records, sObjects)
+}
@@ -0,0 +1,213 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type retrievedAccount struct {
+	Id   string `salesforce:"Id"`
+	Name string `salesforce:"Name"`
+}
+
+func TestSalesforce_GetOne(t *testing.T) {
+	record := map[string]any{
+		"attributes": map[string]string{"type": "Account"},
+		"Id":         "001000000000001AAA",
+		"Name":       "test account",
+	}
+	server, sfAuth, req := setupTestServerWithCapture(record, http.StatusOK)
+	defer server.Close()
+
+	badServer, badSfAuth := setupTestServer("", http.StatusNotFound)
+	defer badServer.Close()
+
+	t.Run("get_with_fields", func(t *testing.T) {
+		got := retrievedAccount{}
+		err := buildSalesforceStruct(&sfAuth).
+			GetOne("Account", "001000000000001AAA", []string{"Id", "Name"}, &got)
+		if err != nil {
+			t.Fatalf("Salesforce.GetOne() error = %v", err)
+		}
+		want := retrievedAccount{Id: "001000000000001AAA", Name: "test account"}
+		if got != want {
+			t.Errorf("Salesforce.GetOne() = %v, want %v", got, want)
+		}
+		wantURI := "/services/data/" + apiVersion + "/sobjects/Account/001000000000001AAA?fields=Id%2CName"
+		if (*req).RequestURI != wantURI {
+			t.Errorf("request uri = %v, want %v", (*req).RequestURI, wantURI)
+		}
+	})
+
+	t.Run("get_into_map", func(t *testing.T) {
+		got := map[string]any{}
+		if err := buildSalesforceStruct(&sfAuth).GetOne("Account", "001", nil, &got); err != nil {
+			t.Fatalf("Salesforce.GetOne() error = %v", err)
+		}
+		if got["Name"] != "test account" {
+			t.Errorf("Salesforce.GetOne() = %v", got)
+		}
+		if (*req).URL.RawQuery != "" {
+			t.Errorf("request query = %v, want none", (*req).URL.RawQuery)
+		}
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		got := retrievedAccount{}
+		if err := buildSalesforceStruct(&badSfAuth).GetOne("Account", "001", nil, &got); err == nil {
+			t.Error("Salesforce.GetOne() expected error")
+		}
+	})
+
+	t.Run("missing_id", func(t *testing.T) {
+		got := retrievedAccount{}
+		if err := buildSalesforceStruct(&sfAuth).GetOne("Account", "", nil, &got); err == nil {
+			t.Error("Salesforce.GetOne() expected error")
+		}
+	})
+
+	t.Run("validation_fail", func(t *testing.T) {
+		got := retrievedAccount{}
+		if err := buildSalesforceStruct(nil).GetOne("Account", "001", nil, &got); err == nil {
+			t.Error("Salesforce.GetOne() expected validation error")
+		}
+	})
+}
+
+func TestSalesforce_GetByExternalId(t *testing.T) {
+	record := map[string]any{"Id": "001000000000001AAA", "Name": "test account"}
+	server, sfAuth, req := setupTestServerWithCapture(record, http.StatusOK)
+	defer server.Close()
+
+	multipleServer, multipleSfAuth := setupTestServer(
+		[]string{
+			"/services/data/v63.0/sobjects/Account/001A",
+			"/services/data/v63.0/sobjects/Account/001B",
+		},
+		http.StatusMultipleChoices,
+	)
+	defer multipleServer.Close()
+
+	t.Run("get_by_external_id", func(t *testing.T) {
+		got := retrievedAccount{}
+		err := buildSalesforceStruct(&sfAuth).
+			GetByExternalId("Account", "External_Id__c", "ext 1", []string{"Name"}, &got)
+		if err != nil {
+			t.Fatalf("Salesforce.GetByExternalId() error = %v", err)
+		}
+		if got.Name != "test account" {
+			t.Errorf("Salesforce.GetByExternalId() = %v", got)
+		}
+		wantPath := "/services/data/" + apiVersion + "/sobjects/Account/External_Id__c/ext 1"
+		if (*req).URL.Path != wantPath {
+			t.Errorf("request path = %v, want %v", (*req).URL.Path, wantPath)
+		}
+	})
+
+	t.Run("multiple_matches", func(t *testing.T) {
+		got := retrievedAccount{}
+		err := buildSalesforceStruct(&multipleSfAuth).
+			GetByExternalId("Account", "External_Id__c", "dup", nil, &got)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusMultipleChoices {
+			t.Errorf("Salesforce.GetByExternalId() error = %v, want 300 APIError", err)
+		}
+	})
+
+	t.Run("missing_field", func(t *testing.T) {
+		got := retrievedAccount{}
+		err := buildSalesforceStruct(&sfAuth).GetByExternalId("Account", "", "ext", nil, &got)
+		if err == nil {
+			t.Error("Salesforce.GetByExternalId() expected error")
+		}
+	})
+
+	t.Run("validation_fail", func(t *testing.T) {
+		got := retrievedAccount{}
+		err := buildSalesforceStruct(nil).
+			GetByExternalId("Account", "External_Id__c", "ext", nil, &got)
+		if err == nil {
+			t.Error("Salesforce.GetByExternalId() expected validation error")
+		}
+	})
+}
+
+func TestSalesforce_GetCollection(t *testing.T) {
+	var requests []sObjectCollectionRetrieve
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		request := sObjectCollectionRetrieve{}
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Fatal(err.Error())
+		}
+		requests = append(requests, request)
+		records := make([]map[string]any, len(request.Ids))
+		for i, id := range request.Ids {
+			if id != "missing" {
+				records[i] = map[string]any{"Id": id, "Name": "name " + id}
+			}
+		}
+		respBody, _ := json.Marshal(records)
+		if _, err := w.Write(respBody); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	t.Run("batched_in_order", func(t *testing.T) {
+		requests = nil
+		ids := make([]string, 2500)
+		for i := range ids {
+			ids[i] = "id" + strconv.Itoa(i)
+		}
+		ids[2001] = "missing"
+		var got []retrievedAccount
+		err := buildSalesforceStruct(&sfAuth).
+			GetCollection("Account", ids, []string{"Id", "Name"}, &got)
+		if err != nil {
+			t.Fatalf("Salesforce.GetCollection() error = %v", err)
+		}
+		if len(requests) != 2 || len(requests[0].Ids) != 2000 || len(requests[1].Ids) != 500 {
+			t.Errorf(
+				"Salesforce.GetCollection() sent %d requests, want batches of 2000 and 500",
+				len(requests),
+			)
+		}
+		if !reflect.DeepEqual(requests[0].Fields, []string{"Id", "Name"}) {
+			t.Errorf("request fields = %v", requests[0].Fields)
+		}
+		if len(got) != len(ids) {
+			t.Fatalf("Salesforce.GetCollection() returned %d records, want %d", len(got), len(ids))
+		}
+		if got[0].Id != ids[0] || got[2499].Name != "name "+ids[2499] {
+			t.Errorf("Salesforce.GetCollection() records out of order: %v, %v", got[0], got[2499])
+		}
+		if got[2001] != (retrievedAccount{}) {
+			t.Errorf("Salesforce.GetCollection() missing record = %v, want zero value", got[2001])
+		}
+	})
+
+	t.Run("no_fields", func(t *testing.T) {
+		var got []retrievedAccount
+		err := buildSalesforceStruct(&sfAuth).GetCollection("Account", []string{"001"}, nil, &got)
+		if err == nil {
+			t.Error("Salesforce.GetCollection() expected error")
+		}
+	})
+
+	t.Run("validation_fail", func(t *testing.T) {
+		var got []retrievedAccount
+		err := buildSalesforceStruct(nil).
+			GetCollection("Account", []string{"001"}, []string{"Id"}, &got)
+		if err == nil {
+			t.Error("Salesforce.GetCollection() expected validation error")
+		}
+	})
+}
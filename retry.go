@@ -0,0 +1,180 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how transient Salesforce failures are retried.
+//
+// Requests using idempotent methods (GET, HEAD, OPTIONS, PUT, DELETE) are retried on network errors,
+// on any of RetryableStatusCodes and on any of RetryableErrorCodes. Other methods (POST, PATCH) are only
+// retried when Salesforce responds with one of SafeErrorCodes, which indicate the request was rejected
+// without being applied, so retrying cannot create duplicate records.
+type RetryPolicy struct {
+	MaxAttempts          int           // total number of attempts, including the first request
+	InitialBackoff       time.Duration // wait before the first retry, doubled for every subsequent retry
+	MaxBackoff           time.Duration // upper bound for the computed backoff
+	RetryableStatusCodes []int         // HTTP status codes retried for idempotent methods
+	RetryableErrorCodes  []string      // Salesforce error codes retried for idempotent methods
+	SafeErrorCodes       []string      // Salesforce error codes retried for every method
+}
+
+const (
+	unableToLockRowError      = "UNABLE_TO_LOCK_ROW"
+	requestLimitExceededError = "REQUEST_LIMIT_EXCEEDED"
+	serverUnavailableError    = "SERVER_UNAVAILABLE"
+)
+
+// DefaultRetryPolicy returns a policy that retries up to 3 times with exponential backoff starting at 500ms.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryableErrorCodes: []string{
+			unableToLockRowError,
+			requestLimitExceededError,
+			serverUnavailableError,
+		},
+		SafeErrorCodes: []string{
+			unableToLockRowError,
+			requestLimitExceededError,
+			serverUnavailableError,
+		},
+	}
+}
+
+func (p RetryPolicy) validate() error {
+	if p.MaxAttempts < 1 {
+		return errors.New("retry policy max attempts must be at least 1")
+	}
+	if p.InitialBackoff < 0 || p.MaxBackoff < 0 {
+		return errors.New("retry policy backoff cannot be negative")
+	}
+	if p.MaxBackoff > 0 && p.MaxBackoff < p.InitialBackoff {
+		return errors.New(
+			"retry policy max backoff must be greater than or equal to initial backoff",
+		)
+	}
+	return nil
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// shouldRetry reports whether the outcome of the given attempt should be retried and how long to wait first.
+// Error responses are buffered so the body can still be read by the caller when no retry happens.
+func (p *RetryPolicy) shouldRetry(
+	ctx context.Context,
+	method string,
+	attempt int,
+	resp *http.Response,
+	err error,
+) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return 0, false
+	}
+	idempotent := isIdempotentMethod(method)
+	if err != nil {
+		return p.backoff(attempt), idempotent
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode <= 300 {
+		return 0, false
+	}
+
+	responseData, readErr := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(responseData))
+	if readErr != nil {
+		return 0, false
+	}
+
+	// the buffered body stays compressed for the caller, only the parsed copy is decompressed
+	errorData := responseData
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		if body, gzErr := decompress(io.NopCloser(bytes.NewReader(responseData))); gzErr == nil {
+			errorData, _ = io.ReadAll(body)
+		}
+	}
+
+	retry := false
+	for _, sfError := range parseSalesforceErrors(errorData) {
+		if slices.Contains(p.SafeErrorCodes, sfError.ErrorCode) ||
+			(idempotent && slices.Contains(p.RetryableErrorCodes, sfError.ErrorCode)) {
+			retry = true
+		}
+	}
+	if idempotent && slices.Contains(p.RetryableStatusCodes, resp.StatusCode) {
+		retry = true
+	}
+	if !retry {
+		return 0, false
+	}
+
+	return max(p.backoff(attempt), retryAfter(resp)), true
+}
+
+// backoff returns the exponential backoff for the given attempt with up to 50% jitter applied.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	if p.InitialBackoff <= 0 {
+		return 0
+	}
+	delay := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// retryAfter parses the Retry-After header, which is either a number of seconds or an HTTP date.
+func retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,281 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func setupFlakyServer(
+	failures int32,
+	status int,
+	errorCode string,
+) (*httptest.Server, authentication, *atomic.Int32) {
+	var calls atomic.Int32
+	body, _ := json.Marshal([]SalesforceErrorMessage{{
+		Message:   "transient failure",
+		ErrorCode: errorCode,
+	}})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(status)
+			if _, err := w.Write(body); err != nil {
+				panic(err)
+			}
+			return
+		}
+		if _, err := w.Write([]byte(`{"id":"123abc","success":true}`)); err != nil {
+			panic(err)
+		}
+	}))
+	return server, authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}, &calls
+}
+
+func testRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 2 * time.Millisecond
+	return policy
+}
+
+func Test_doRequest_retry(t *testing.T) {
+	policy := testRetryPolicy()
+	tests := []struct {
+		name      string
+		policy    *RetryPolicy
+		method    string
+		failures  int32
+		status    int
+		errorCode string
+		wantCalls int32
+		wantErr   bool
+	}{
+		{
+			name:      "no_policy_no_retry",
+			policy:    nil,
+			method:    http.MethodGet,
+			failures:  1,
+			status:    http.StatusServiceUnavailable,
+			errorCode: serverUnavailableError,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "retry_get_on_status_code",
+			policy:    &policy,
+			method:    http.MethodGet,
+			failures:  2,
+			status:    http.StatusBadGateway,
+			errorCode: "",
+			wantCalls: 3,
+			wantErr:   false,
+		},
+		{
+			name:      "retry_post_on_safe_error_code",
+			policy:    &policy,
+			method:    http.MethodPost,
+			failures:  1,
+			status:    http.StatusBadRequest,
+			errorCode: unableToLockRowError,
+			wantCalls: 2,
+			wantErr:   false,
+		},
+		{
+			name:      "no_retry_post_on_status_code",
+			policy:    &policy,
+			method:    http.MethodPost,
+			failures:  1,
+			status:    http.StatusBadGateway,
+			errorCode: "",
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "no_retry_on_other_error_code",
+			policy:    &policy,
+			method:    http.MethodGet,
+			failures:  1,
+			status:    http.StatusBadRequest,
+			errorCode: "DUPLICATE_VALUE",
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "give_up_after_max_attempts",
+			policy:    &policy,
+			method:    http.MethodGet,
+			failures:  10,
+			status:    http.StatusServiceUnavailable,
+			errorCode: serverUnavailableError,
+			wantCalls: 4,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, sfAuth, calls := setupFlakyServer(tt.failures, tt.status, tt.errorCode)
+			defer server.Close()
+			config := getDefaultConfig(t)
+			config.retryPolicy = tt.policy
+
+			_, err := doRequest(context.Background(), &sfAuth, config, requestPayload{
+				method:  tt.method,
+				uri:     "/sobjects/Account",
+				content: jsonType,
+				body:    `{"Name":"test"}`,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("doRequest() calls = %v, want %v", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func Test_doRequest_retryCompressedError(t *testing.T) {
+	var calls atomic.Int32
+	body, _ := json.Marshal([]SalesforceErrorMessage{{
+		Message:   "transient failure",
+		ErrorCode: unableToLockRowError,
+	}})
+	compressed, err := compress(string(body))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	compressedBody, _ := io.ReadAll(compressed)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.WriteHeader(http.StatusBadRequest)
+			if _, err := w.Write(compressedBody); err != nil {
+				panic(err)
+			}
+			return
+		}
+		if _, err := w.Write([]byte(`{"id":"123abc","success":true}`)); err != nil {
+			panic(err)
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+	config := getDefaultConfig(t)
+	policy := testRetryPolicy()
+	config.retryPolicy = &policy
+	config.compressionHeaders = true
+
+	_, err = doRequest(context.Background(), &sfAuth, config, requestPayload{
+		method:   http.MethodPost,
+		uri:      "/sobjects/Account",
+		content:  jsonType,
+		body:     `{"Name":"test"}`,
+		compress: true,
+	})
+	if err != nil {
+		t.Errorf("doRequest() error = %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("doRequest() calls = %v, want 2", got)
+	}
+}
+
+func Test_doRequest_retryStopsOnContextCancel(t *testing.T) {
+	server, sfAuth, calls := setupFlakyServer(10, http.StatusServiceUnavailable, "")
+	defer server.Close()
+	config := getDefaultConfig(t)
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Hour
+	policy.MaxBackoff = time.Hour
+	config.retryPolicy = &policy
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := doRequest(ctx, &sfAuth, config, requestPayload{
+		method:  http.MethodGet,
+		uri:     "/limits",
+		content: jsonType,
+	})
+	if err == nil {
+		t.Error("doRequest() expected error when context is done before retry")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("doRequest() calls = %v, want 1", got)
+	}
+}
+
+func Test_doRequest_streamedBodyNotRetried(t *testing.T) {
+	server, sfAuth, calls := setupFlakyServer(10, http.StatusServiceUnavailable, "")
+	defer server.Close()
+	config := getDefaultConfig(t)
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	config.retryPolicy = &policy
+
+	_, err := doRequest(context.Background(), &sfAuth, config, requestPayload{
+		method:     http.MethodPut,
+		uri:        "/jobs/ingest/750/batches",
+		content:    csvType,
+		bodyReader: strings.NewReader("Name\ntest\n"),
+	})
+	if err == nil {
+		t.Error("doRequest() expected error")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("doRequest() calls = %v, want 1", got)
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:    10,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}
+	tests := []struct {
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{attempt: 1, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{attempt: 2, min: 100 * time.Millisecond, max: 200 * time.Millisecond},
+		{attempt: 3, min: 200 * time.Millisecond, max: 400 * time.Millisecond},
+		{attempt: 8, min: 500 * time.Millisecond, max: time.Second},
+	}
+	for _, tt := range tests {
+		got := policy.backoff(tt.attempt)
+		if got < tt.min || got > tt.max {
+			t.Errorf("backoff(%d) = %v, want between %v and %v", tt.attempt, got, tt.min, tt.max)
+		}
+	}
+}
+
+func Test_retryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+	}{
+		{name: "missing", header: "", want: 0},
+		{name: "seconds", header: "3", want: 3 * time.Second},
+		{name: "invalid", header: "soon", want: 0},
+		{name: "date_in_past", header: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set("Retry-After", tt.header)
+			}
+			if got := retryAfter(resp); got != tt.want {
+				t.Errorf("retryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package salesforce
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"reflect"
 	"slices"
@@ -13,10 +15,14 @@ import (
 	"github.com/forcedotcom/go-soql"
 )
 
+// Salesforce is a client for a single Salesforce org, created by Init.
+// It is safe for concurrent use by multiple goroutines; when the session expires, concurrent requests
+// share a single refresh of the access token.
 type Salesforce struct {
-	auth     *authentication
-	config   *configuration
-	AuthFlow AuthFlowType
+	auth      *authentication
+	config    *configuration
+	describes *describeCache
+	AuthFlow  AuthFlowType
 }
 
 type SalesforceErrorMessage struct {
@@ -44,6 +50,7 @@ const (
 	batchSizeMax                  = 200
 	bulkBatchSizeMax              = 10000
 	bulkPollTimeout               = time.Duration(1 * time.Minute)
+	bulkPollInterval              = time.Second / 2
 	invalidSessionIdError         = "INVALID_SESSION_ID"
 	httpDefaultMaxIdleConnections = 10
 	httpDefaultIdleConnTimeout    = time.Duration(30 * time.Second)
@@ -58,6 +65,15 @@ func validateOfTypeSlice(data any) error {
 	return nil
 }
 
+// validateOfTypePointerToSlice checks for a pointer to a slice, so the records can be updated in place
+func validateOfTypePointerToSlice(data any) error {
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Slice {
+		return errors.New("expected a pointer to a slice, got: " + value.Kind().String())
+	}
+	return nil
+}
+
 func validateOfTypeStructOrMap(data any) error {
 	t := reflect.TypeOf(data).Kind().String()
 	if t != "struct" && t != "map" {
@@ -166,6 +182,11 @@ func validateObjectWithAssignmentRuleId(sObjectName string) error {
 }
 
 func Init(creds Creds, options ...Option) (*Salesforce, error) {
+	return InitCtx(context.Background(), creds, options...)
+}
+
+// InitCtx is like Init but uses ctx for the authentication requests.
+func InitCtx(ctx context.Context, creds Creds, options ...Option) (*Salesforce, error) {
 	var auth *authentication
 	var err error
 	var authFlow AuthFlowType
@@ -187,10 +208,30 @@ func Init(creds Creds, options ...Option) (*Salesforce, error) {
 		return nil, errors.New("creds is empty")
 	}
 
-	// Determine authentication flow and authenticate
-	if creds.Domain != "" && creds.ConsumerKey != "" && creds.ConsumerSecret != "" &&
-		creds.Username != "" && creds.Password != "" && creds.SecurityToken != "" {
-		auth, err = usernamePasswordFlow(
+	authFlow = selectAuthFlow(creds)
+
+	// an access token passed explicitly is used and saved instead of the stored token
+	if config.tokenStore != nil && authFlow != AuthFlowUnknown && authFlow != AuthFlowAccessToken {
+		token, err := config.tokenStore.Load(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("loading token from store: %w", err)
+		}
+		if token != nil && token.AccessToken != "" && token.InstanceUrl != "" {
+			auth = &authentication{
+				grantType: authFlow.grantType(),
+				creds:     creds,
+				session:   &session{},
+			}
+			auth.setToken(*token)
+			return newSalesforce(auth, config, authFlow), nil
+		}
+	}
+
+	// Authenticate with the selected flow
+	switch authFlow {
+	case AuthFlowUsernamePassword:
+		auth, err = config.usernamePasswordFlow(
+			ctx,
 			creds.Domain,
 			creds.Username,
 			creds.Password,
@@ -198,30 +239,32 @@ func Init(creds Creds, options ...Option) (*Salesforce, error) {
 			creds.ConsumerKey,
 			creds.ConsumerSecret,
 		)
-		authFlow = AuthFlowUsernamePassword
-	} else if creds.Domain != "" && creds.ConsumerKey != "" && creds.ConsumerSecret != "" {
-		auth, err = clientCredentialsFlow(
+	case AuthFlowRefreshToken:
+		auth, err = config.refreshTokenFlow(
+			ctx,
 			creds.Domain,
+			creds.RefreshToken,
 			creds.ConsumerKey,
 			creds.ConsumerSecret,
 		)
-		authFlow = AuthFlowClientCredentials
-	} else if creds.AccessToken != "" {
-		auth, err = config.getAccessTokenAuthentication(
+	case AuthFlowClientCredentials:
+		auth, err = config.clientCredentialsFlow(
+			ctx,
 			creds.Domain,
-			creds.AccessToken,
+			creds.ConsumerKey,
+			creds.ConsumerSecret,
 		)
-		authFlow = AuthFlowAccessToken
-	} else if creds.Domain != "" && creds.Username != "" &&
-		creds.ConsumerKey != "" && creds.ConsumerRSAPem != "" {
-		auth, err = jwtFlow(
+	case AuthFlowAccessToken:
+		auth, err = config.getAccessTokenAuthentication(ctx, creds)
+	case AuthFlowJWT:
+		auth, err = config.jwtFlow(
+			ctx,
 			creds.Domain,
 			creds.Username,
 			creds.ConsumerKey,
 			creds.ConsumerRSAPem,
 			JwtExpirationTime,
 		)
-		authFlow = AuthFlowJWT
 	}
 
 	if err != nil {
@@ -229,13 +272,31 @@ func Init(creds Creds, options ...Option) (*Salesforce, error) {
 	} else if auth == nil || auth.AccessToken == "" {
 		return nil, errors.New("unknown authentication error")
 	}
+	if auth.RefreshToken != "" && creds.RefreshToken != auth.RefreshToken {
+		creds.RefreshToken = auth.RefreshToken // the connected app rotates refresh tokens
+	}
+	if auth.creds.RefreshToken != "" { // refreshed while the access token was validated
+		creds.RefreshToken = auth.creds.RefreshToken
+	}
 	auth.creds = creds
+	auth.session = &session{}
 
+	if config.tokenStore != nil {
+		if err := config.tokenStore.Save(ctx, auth.token()); err != nil {
+			return nil, fmt.Errorf("saving token to store: %w", err)
+		}
+	}
+
+	return newSalesforce(auth, config, authFlow), nil
+}
+
+func newSalesforce(auth *authentication, config *configuration, authFlow AuthFlowType) *Salesforce {
 	return &Salesforce{
-		auth:     auth,
-		config:   config,
-		AuthFlow: authFlow,
-	}, nil
+		auth:      auth,
+		config:    config,
+		describes: newDescribeCache(),
+		AuthFlow:  authFlow,
+	}
 }
 
 func (sf *Salesforce) DoRequest(
@@ -243,13 +304,30 @@ func (sf *Salesforce) DoRequest(
 	uri string,
 	body []byte,
 	opts ...RequestOption,
+) (*http.Response, error) {
+	return sf.DoRequestCtx(
+		context.Background(),
+		method,
+		uri,
+		body,
+		opts...,
+	)
+}
+
+// DoRequestCtx is like DoRequest but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) DoRequestCtx(
+	ctx context.Context,
+	method string,
+	uri string,
+	body []byte,
+	opts ...RequestOption,
 ) (*http.Response, error) {
 	authErr := validateAuth(*sf)
 	if authErr != nil {
 		return nil, authErr
 	}
 
-	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+	resp, err := doRequest(ctx, sf.auth, sf.config, requestPayload{
 		method:   method,
 		uri:      uri,
 		content:  jsonType,
@@ -269,13 +347,30 @@ func (sf *Salesforce) DoApexRequest(
 	uri string,
 	body []byte,
 	opts ...RequestOption,
+) (*http.Response, error) {
+	return sf.DoApexRequestCtx(
+		context.Background(),
+		method,
+		uri,
+		body,
+		opts...,
+	)
+}
+
+// DoApexRequestCtx is like DoApexRequest but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) DoApexRequestCtx(
+	ctx context.Context,
+	method string,
+	uri string,
+	body []byte,
+	opts ...RequestOption,
 ) (*http.Response, error) {
 	authErr := validateAuth(*sf)
 	if authErr != nil {
 		return nil, authErr
 	}
 
-	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+	resp, err := doRequest(ctx, sf.auth, sf.config, requestPayload{
 		method:       method,
 		uri:          uri,
 		content:      jsonType,
@@ -291,13 +386,62 @@ func (sf *Salesforce) DoApexRequest(
 	return resp, nil
 }
 
+// APIVersion describes a REST API version supported by the org
+type APIVersion struct {
+	Label   string `json:"label"`
+	Url     string `json:"url"`
+	Version string `json:"version"`
+}
+
+// ListAPIVersions returns the REST API versions available in the org, oldest first.
+// Prefix a version with "v" to use it with WithAPIVersion.
+func (sf *Salesforce) ListAPIVersions() ([]APIVersion, error) {
+	return sf.ListAPIVersionsCtx(context.Background())
+}
+
+// ListAPIVersionsCtx is like ListAPIVersions but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) ListAPIVersionsCtx(ctx context.Context) ([]APIVersion, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	resp, err := doRequest(ctx, sf.auth, sf.config, requestPayload{
+		method:       http.MethodGet,
+		uri:          "",
+		content:      jsonType,
+		compress:     sf.config.compressionHeaders,
+		endpointBase: "/services/data",
+	})
+	if err != nil {
+		return nil, err
+	}
+	respBody, readErr := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	versions := []APIVersion{}
+	if err := json.Unmarshal(respBody, &versions); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
 func (sf *Salesforce) Query(query string, sObject any) error {
+	return sf.QueryCtx(context.Background(), query, sObject)
+}
+
+// QueryCtx is like Query but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) QueryCtx(ctx context.Context, query string, sObject any) error {
 	authErr := validateAuth(*sf)
 	if authErr != nil {
 		return authErr
 	}
 
-	queryErr := performQuery(sf, query, sObject)
+	queryErr := performQuery(ctx, sf, queryResource, query, sObject)
 	if queryErr != nil {
 		return queryErr
 	}
@@ -305,7 +449,71 @@ func (sf *Salesforce) Query(query string, sObject any) error {
 	return nil
 }
 
+// QueryIterator performs a query and returns a QueryIterator that fetches the results one page at a time.
+func (sf *Salesforce) QueryIterator(query string) (*QueryIterator, error) {
+	return sf.QueryIteratorCtx(context.Background(), query)
+}
+
+// QueryIteratorCtx is like QueryIterator but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) QueryIteratorCtx(ctx context.Context, query string) (*QueryIterator, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	return newQueryIterator(ctx, sf, queryResource, query), nil
+}
+
 func (sf *Salesforce) QueryStruct(soqlStruct any, sObject any) error {
+	return sf.QueryStructCtx(context.Background(), soqlStruct, sObject)
+}
+
+// QueryStructCtx is like QueryStruct but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) QueryStructCtx(ctx context.Context, soqlStruct any, sObject any) error {
+	validationErr := validateGoSoql(*sf, soqlStruct)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	soqlQuery, err := soql.Marshal(soqlStruct)
+	if err != nil {
+		return err
+	}
+	queryErr := performQuery(ctx, sf, queryResource, soqlQuery, sObject)
+	if queryErr != nil {
+		return queryErr
+	}
+
+	return nil
+}
+
+// QueryAll is like Query but includes deleted records and archived activities.
+func (sf *Salesforce) QueryAll(query string, sObject any) error {
+	return sf.QueryAllCtx(context.Background(), query, sObject)
+}
+
+// QueryAllCtx is like QueryAll but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) QueryAllCtx(ctx context.Context, query string, sObject any) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+
+	queryErr := performQuery(ctx, sf, queryAllResource, query, sObject)
+	if queryErr != nil {
+		return queryErr
+	}
+
+	return nil
+}
+
+// QueryStructAll is like QueryStruct but includes deleted records and archived activities.
+func (sf *Salesforce) QueryStructAll(soqlStruct any, sObject any) error {
+	return sf.QueryStructAllCtx(context.Background(), soqlStruct, sObject)
+}
+
+// QueryStructAllCtx is like QueryStructAll but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) QueryStructAllCtx(ctx context.Context, soqlStruct any, sObject any) error {
 	validationErr := validateGoSoql(*sf, soqlStruct)
 	if validationErr != nil {
 		return validationErr
@@ -315,7 +523,7 @@ func (sf *Salesforce) QueryStruct(soqlStruct any, sObject any) error {
 	if err != nil {
 		return err
 	}
-	queryErr := performQuery(sf, soqlQuery, sObject)
+	queryErr := performQuery(ctx, sf, queryAllResource, soqlQuery, sObject)
 	if queryErr != nil {
 		return queryErr
 	}
@@ -323,44 +531,212 @@ func (sf *Salesforce) QueryStruct(soqlStruct any, sObject any) error {
 	return nil
 }
 
+// DescribeGlobal lists the objects available in the org.
+// The result is cached and only downloaded again when the org's metadata changed.
+func (sf *Salesforce) DescribeGlobal() (DescribeGlobalResult, error) {
+	return sf.DescribeGlobalCtx(context.Background())
+}
+
+// DescribeGlobalCtx is like DescribeGlobal but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) DescribeGlobalCtx(ctx context.Context) (DescribeGlobalResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return DescribeGlobalResult{}, authErr
+	}
+
+	result := DescribeGlobalResult{}
+	if err := doDescribe(ctx, sf, "/sobjects", &result); err != nil {
+		return DescribeGlobalResult{}, err
+	}
+
+	return result, nil
+}
+
+// DescribeSObject returns the fields, relationships and record types of an object.
+// The result is cached and only downloaded again when the object's metadata changed.
+func (sf *Salesforce) DescribeSObject(sObjectName string) (SObjectDescribe, error) {
+	return sf.DescribeSObjectCtx(context.Background(), sObjectName)
+}
+
+// DescribeSObjectCtx is like DescribeSObject but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) DescribeSObjectCtx(
+	ctx context.Context,
+	sObjectName string,
+) (SObjectDescribe, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SObjectDescribe{}, authErr
+	}
+	if sObjectName == "" {
+		return SObjectDescribe{}, errors.New("sObject name is required")
+	}
+
+	result := SObjectDescribe{}
+	if err := doDescribe(ctx, sf, "/sobjects/"+sObjectName+"/describe", &result); err != nil {
+		return SObjectDescribe{}, err
+	}
+
+	return result, nil
+}
+
+// GetOne retrieves the record with the given Id and decodes it into sObject.
+// If fields is empty, all fields are returned.
+func (sf *Salesforce) GetOne(sObjectName string, id string, fields []string, sObject any) error {
+	return sf.GetOneCtx(context.Background(), sObjectName, id, fields, sObject)
+}
+
+// GetOneCtx is like GetOne but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) GetOneCtx(
+	ctx context.Context,
+	sObjectName string,
+	id string,
+	fields []string,
+	sObject any,
+) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+
+	return doGetOne(ctx, sf, sObjectName, id, fields, sObject)
+}
+
+// GetByExternalId retrieves the record with the given external id value and decodes it into sObject.
+// If fields is empty, all fields are returned.
+func (sf *Salesforce) GetByExternalId(
+	sObjectName string,
+	externalIdFieldName string,
+	externalId string,
+	fields []string,
+	sObject any,
+) error {
+	return sf.GetByExternalIdCtx(
+		context.Background(),
+		sObjectName,
+		externalIdFieldName,
+		externalId,
+		fields,
+		sObject,
+	)
+}
+
+// GetByExternalIdCtx is like GetByExternalId but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) GetByExternalIdCtx(
+	ctx context.Context,
+	sObjectName string,
+	externalIdFieldName string,
+	externalId string,
+	fields []string,
+	sObject any,
+) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+
+	return doGetByExternalId(ctx, sf, sObjectName, externalIdFieldName, externalId, fields, sObject)
+}
+
+// GetCollection retrieves the records with the given Ids and decodes them into sObjects,
+// which should be a pointer to a slice. Ids are sent in batches of up to 2000.
+// Records are in the order of ids; ids that are not found decode to zero values.
+func (sf *Salesforce) GetCollection(
+	sObjectName string,
+	ids []string,
+	fields []string,
+	sObjects any,
+) error {
+	return sf.GetCollectionCtx(context.Background(), sObjectName, ids, fields, sObjects)
+}
+
+// GetCollectionCtx is like GetCollection but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) GetCollectionCtx(
+	ctx context.Context,
+	sObjectName string,
+	ids []string,
+	fields []string,
+	sObjects any,
+) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+
+	return doGetCollection(ctx, sf, sObjectName, ids, fields, sObjects)
+}
+
 func (sf *Salesforce) InsertOne(sObjectName string, record any) (SalesforceResult, error) {
+	return sf.InsertOneCtx(context.Background(), sObjectName, record)
+}
+
+// InsertOneCtx is like InsertOne but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) InsertOneCtx(
+	ctx context.Context,
+	sObjectName string,
+	record any,
+) (SalesforceResult, error) {
 	validationErr := validateSingles(*sf, record)
 	if validationErr != nil {
 		return SalesforceResult{}, validationErr
 	}
 
-	return doInsertOne(sf, sObjectName, record)
+	return doInsertOne(ctx, sf, sObjectName, record)
 }
 
 func (sf *Salesforce) UpdateOne(sObjectName string, record any) error {
+	return sf.UpdateOneCtx(context.Background(), sObjectName, record)
+}
+
+// UpdateOneCtx is like UpdateOne but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) UpdateOneCtx(ctx context.Context, sObjectName string, record any) error {
 	validationErr := validateSingles(*sf, record)
 	if validationErr != nil {
 		return validationErr
 	}
 
-	return doUpdateOne(sf, sObjectName, record)
+	return doUpdateOne(ctx, sf, sObjectName, record)
 }
 
 func (sf *Salesforce) UpsertOne(
 	sObjectName string,
 	externalIdFieldName string,
 	record any,
+) (SalesforceResult, error) {
+	return sf.UpsertOneCtx(
+		context.Background(),
+		sObjectName,
+		externalIdFieldName,
+		record,
+	)
+}
+
+// UpsertOneCtx is like UpsertOne but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) UpsertOneCtx(
+	ctx context.Context,
+	sObjectName string,
+	externalIdFieldName string,
+	record any,
 ) (SalesforceResult, error) {
 	validationErr := validateSingles(*sf, record)
 	if validationErr != nil {
 		return SalesforceResult{}, validationErr
 	}
 
-	return doUpsertOne(sf, sObjectName, externalIdFieldName, record)
+	return doUpsertOne(ctx, sf, sObjectName, externalIdFieldName, record)
 }
 
 func (sf *Salesforce) DeleteOne(sObjectName string, record any) error {
+	return sf.DeleteOneCtx(context.Background(), sObjectName, record)
+}
+
+// DeleteOneCtx is like DeleteOne but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) DeleteOneCtx(ctx context.Context, sObjectName string, record any) error {
 	validationErr := validateSingles(*sf, record)
 	if validationErr != nil {
 		return validationErr
 	}
 
-	return doDeleteOne(sf, sObjectName, record)
+	return doDeleteOne(ctx, sf, sObjectName, record)
 }
 
 func (sf *Salesforce) InsertCollection(
@@ -368,15 +744,17 @@ func (sf *Salesforce) InsertCollection(
 	records any,
 	batchSize int,
 ) (SalesforceResults, error) {
-	validationErr := validateCollections(*sf, records, batchSize)
-	if validationErr != nil {
-		return SalesforceResults{}, validationErr
-	}
-
-	return doInsertCollection(sf, sObjectName, records, batchSize)
+	return sf.InsertCollectionCtx(
+		context.Background(),
+		sObjectName,
+		records,
+		batchSize,
+	)
 }
 
-func (sf *Salesforce) UpdateCollection(
+// InsertCollectionCtx is like InsertCollection but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) InsertCollectionCtx(
+	ctx context.Context,
 	sObjectName string,
 	records any,
 	batchSize int,
@@ -386,107 +764,347 @@ func (sf *Salesforce) UpdateCollection(
 		return SalesforceResults{}, validationErr
 	}
 
-	return doUpdateCollection(sf, sObjectName, records, batchSize)
+	return doInsertCollection(ctx, sf, sObjectName, records, batchSize)
 }
 
-func (sf *Salesforce) UpsertCollection(
+// InsertCollectionWithIds is like InsertCollection but takes a pointer to a slice of records
+// and writes the Id of each created record into it.
+func (sf *Salesforce) InsertCollectionWithIds(
 	sObjectName string,
-	externalIdFieldName string,
 	records any,
 	batchSize int,
 ) (SalesforceResults, error) {
-	validationErr := validateCollections(*sf, records, batchSize)
-	if validationErr != nil {
-		return SalesforceResults{}, validationErr
-	}
-
-	return doUpsertCollection(sf, sObjectName, externalIdFieldName, records, batchSize)
+	return sf.InsertCollectionWithIdsCtx(context.Background(), sObjectName, records, batchSize)
 }
 
-func (sf *Salesforce) DeleteCollection(
+// InsertCollectionWithIdsCtx is like InsertCollectionWithIds but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) InsertCollectionWithIdsCtx(
+	ctx context.Context,
 	sObjectName string,
 	records any,
 	batchSize int,
 ) (SalesforceResults, error) {
-	validationErr := validateCollections(*sf, records, batchSize)
+	if typErr := validateOfTypePointerToSlice(records); typErr != nil {
+		return SalesforceResults{}, typErr
+	}
+	slice := reflect.ValueOf(records).Elem().Interface()
+	validationErr := validateCollections(*sf, slice, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
 
-	return doDeleteCollection(sf, sObjectName, records, batchSize)
+	results, err := doInsertCollection(ctx, sf, sObjectName, slice, batchSize)
+	if assignErr := assignResults(records, results.Results); assignErr != nil {
+		return results, assignErr
+	}
+	return results, err
 }
 
-func (sf *Salesforce) InsertComposite(
+func (sf *Salesforce) UpdateCollection(
 	sObjectName string,
 	records any,
 	batchSize int,
-	allOrNone bool,
 ) (SalesforceResults, error) {
-	validationErr := validateCollections(*sf, records, batchSize)
-	if validationErr != nil {
-		return SalesforceResults{}, validationErr
-	}
-
-	return doInsertComposite(sf, sObjectName, records, allOrNone, batchSize)
+	return sf.UpdateCollectionCtx(
+		context.Background(),
+		sObjectName,
+		records,
+		batchSize,
+	)
 }
 
-func (sf *Salesforce) UpdateComposite(
+// UpdateCollectionCtx is like UpdateCollection but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) UpdateCollectionCtx(
+	ctx context.Context,
 	sObjectName string,
 	records any,
 	batchSize int,
-	allOrNone bool,
 ) (SalesforceResults, error) {
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
 
-	return doUpdateComposite(sf, sObjectName, records, allOrNone, batchSize)
+	return doUpdateCollection(ctx, sf, sObjectName, records, batchSize)
 }
 
-func (sf *Salesforce) UpsertComposite(
+func (sf *Salesforce) UpsertCollection(
+	sObjectName string,
+	externalIdFieldName string,
+	records any,
+	batchSize int,
+) (SalesforceResults, error) {
+	return sf.UpsertCollectionCtx(
+		context.Background(),
+		sObjectName,
+		externalIdFieldName,
+		records,
+		batchSize,
+	)
+}
+
+// UpsertCollectionCtx is like UpsertCollection but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) UpsertCollectionCtx(
+	ctx context.Context,
 	sObjectName string,
 	externalIdFieldName string,
 	records any,
 	batchSize int,
-	allOrNone bool,
 ) (SalesforceResults, error) {
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
 
-	return doUpsertComposite(sf, sObjectName, externalIdFieldName, records, allOrNone, batchSize)
+	return doUpsertCollection(ctx, sf, sObjectName, externalIdFieldName, records, batchSize)
 }
 
-func (sf *Salesforce) DeleteComposite(
+func (sf *Salesforce) DeleteCollection(
+	sObjectName string,
+	records any,
+	batchSize int,
+) (SalesforceResults, error) {
+	return sf.DeleteCollectionCtx(
+		context.Background(),
+		sObjectName,
+		records,
+		batchSize,
+	)
+}
+
+// DeleteCollectionCtx is like DeleteCollection but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) DeleteCollectionCtx(
+	ctx context.Context,
 	sObjectName string,
 	records any,
 	batchSize int,
-	allOrNone bool,
 ) (SalesforceResults, error) {
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
 
-	return doDeleteComposite(sf, sObjectName, records, allOrNone, batchSize)
+	return doDeleteCollection(ctx, sf, sObjectName, records, batchSize)
 }
 
-func (sf *Salesforce) QueryBulkExport(query string, filePath string) error {
-	authErr := validateAuth(*sf)
-	if authErr != nil {
-		return authErr
-	}
-	queryErr := doQueryBulk(sf, filePath, query)
-	if queryErr != nil {
-		return queryErr
+func (sf *Salesforce) InsertComposite(
+	sObjectName string,
+	records any,
+	batchSize int,
+	allOrNone bool,
+) (SalesforceResults, error) {
+	return sf.InsertCompositeCtx(
+		context.Background(),
+		sObjectName,
+		records,
+		batchSize,
+		allOrNone,
+	)
+}
+
+// InsertCompositeCtx is like InsertComposite but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) InsertCompositeCtx(
+	ctx context.Context,
+	sObjectName string,
+	records any,
+	batchSize int,
+	allOrNone bool,
+) (SalesforceResults, error) {
+	validationErr := validateCollections(*sf, records, batchSize)
+	if validationErr != nil {
+		return SalesforceResults{}, validationErr
+	}
+
+	return doInsertComposite(ctx, sf, sObjectName, records, allOrNone, batchSize)
+}
+
+// InsertCompositeWithIds is like InsertComposite but takes a pointer to a slice of records
+// and writes the Id of each created record into it.
+func (sf *Salesforce) InsertCompositeWithIds(
+	sObjectName string,
+	records any,
+	batchSize int,
+	allOrNone bool,
+) (SalesforceResults, error) {
+	return sf.InsertCompositeWithIdsCtx(
+		context.Background(),
+		sObjectName,
+		records,
+		batchSize,
+		allOrNone,
+	)
+}
+
+// InsertCompositeWithIdsCtx is like InsertCompositeWithIds but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) InsertCompositeWithIdsCtx(
+	ctx context.Context,
+	sObjectName string,
+	records any,
+	batchSize int,
+	allOrNone bool,
+) (SalesforceResults, error) {
+	if typErr := validateOfTypePointerToSlice(records); typErr != nil {
+		return SalesforceResults{}, typErr
+	}
+	slice := reflect.ValueOf(records).Elem().Interface()
+	validationErr := validateCollections(*sf, slice, batchSize)
+	if validationErr != nil {
+		return SalesforceResults{}, validationErr
+	}
+
+	results, err := doInsertComposite(ctx, sf, sObjectName, slice, allOrNone, batchSize)
+	if assignErr := assignResults(records, results.Results); assignErr != nil {
+		return results, assignErr
+	}
+	return results, err
+}
+
+func (sf *Salesforce) UpdateComposite(
+	sObjectName string,
+	records any,
+	batchSize int,
+	allOrNone bool,
+) (SalesforceResults, error) {
+	return sf.UpdateCompositeCtx(
+		context.Background(),
+		sObjectName,
+		records,
+		batchSize,
+		allOrNone,
+	)
+}
+
+// UpdateCompositeCtx is like UpdateComposite but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) UpdateCompositeCtx(
+	ctx context.Context,
+	sObjectName string,
+	records any,
+	batchSize int,
+	allOrNone bool,
+) (SalesforceResults, error) {
+	validationErr := validateCollections(*sf, records, batchSize)
+	if validationErr != nil {
+		return SalesforceResults{}, validationErr
+	}
+
+	return doUpdateComposite(ctx, sf, sObjectName, records, allOrNone, batchSize)
+}
+
+func (sf *Salesforce) UpsertComposite(
+	sObjectName string,
+	externalIdFieldName string,
+	records any,
+	batchSize int,
+	allOrNone bool,
+) (SalesforceResults, error) {
+	return sf.UpsertCompositeCtx(
+		context.Background(),
+		sObjectName,
+		externalIdFieldName,
+		records,
+		batchSize,
+		allOrNone,
+	)
+}
+
+// UpsertCompositeCtx is like UpsertComposite but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) UpsertCompositeCtx(
+	ctx context.Context,
+	sObjectName string,
+	externalIdFieldName string,
+	records any,
+	batchSize int,
+	allOrNone bool,
+) (SalesforceResults, error) {
+	validationErr := validateCollections(*sf, records, batchSize)
+	if validationErr != nil {
+		return SalesforceResults{}, validationErr
+	}
+
+	return doUpsertComposite(
+		ctx,
+		sf,
+		sObjectName,
+		externalIdFieldName,
+		records,
+		allOrNone,
+		batchSize,
+	)
+}
+
+func (sf *Salesforce) DeleteComposite(
+	sObjectName string,
+	records any,
+	batchSize int,
+	allOrNone bool,
+) (SalesforceResults, error) {
+	return sf.DeleteCompositeCtx(
+		context.Background(),
+		sObjectName,
+		records,
+		batchSize,
+		allOrNone,
+	)
+}
+
+// DeleteCompositeCtx is like DeleteComposite but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) DeleteCompositeCtx(
+	ctx context.Context,
+	sObjectName string,
+	records any,
+	batchSize int,
+	allOrNone bool,
+) (SalesforceResults, error) {
+	validationErr := validateCollections(*sf, records, batchSize)
+	if validationErr != nil {
+		return SalesforceResults{}, validationErr
+	}
+
+	return doDeleteComposite(ctx, sf, sObjectName, records, allOrNone, batchSize)
+}
+
+func (sf *Salesforce) QueryBulkExport(
+	query string,
+	filePath string,
+	opts ...BulkJobOption,
+) error {
+	return sf.QueryBulkExportCtx(context.Background(), query, filePath, opts...)
+}
+
+// QueryBulkExportCtx is like QueryBulkExport but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) QueryBulkExportCtx(
+	ctx context.Context,
+	query string,
+	filePath string,
+	opts ...BulkJobOption,
+) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	queryErr := doQueryBulk(ctx, sf, filePath, query, opts...)
+	if queryErr != nil {
+		return queryErr
 	}
 
 	return nil
 }
 
-func (sf *Salesforce) QueryStructBulkExport(soqlStruct any, filePath string) error {
+func (sf *Salesforce) QueryStructBulkExport(
+	soqlStruct any,
+	filePath string,
+	opts ...BulkJobOption,
+) error {
+	return sf.QueryStructBulkExportCtx(context.Background(), soqlStruct, filePath, opts...)
+}
+
+// QueryStructBulkExportCtx is like QueryStructBulkExport but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) QueryStructBulkExportCtx(
+	ctx context.Context,
+	soqlStruct any,
+	filePath string,
+	opts ...BulkJobOption,
+) error {
 	validationErr := validateGoSoql(*sf, soqlStruct)
 	if validationErr != nil {
 		return validationErr
@@ -496,7 +1114,7 @@ func (sf *Salesforce) QueryStructBulkExport(soqlStruct any, filePath string) err
 	if err != nil {
 		return err
 	}
-	queryErr := doQueryBulk(sf, filePath, soqlQuery)
+	queryErr := doQueryBulk(ctx, sf, filePath, soqlQuery, opts...)
 	if queryErr != nil {
 		return queryErr
 	}
@@ -504,346 +1122,1155 @@ func (sf *Salesforce) QueryStructBulkExport(soqlStruct any, filePath string) err
 	return nil
 }
 
-func (sf *Salesforce) QueryBulkIterator(query string) (IteratorJob, error) {
-	authErr := validateAuth(*sf)
-	if authErr != nil {
-		return nil, authErr
-	}
-	queryJobReq := bulkQueryJobCreationRequest{
-		Operation: queryJobType,
-		Query:     query,
+// QueryBulkExportTo writes the results of a bulk query to w as csv, one page at a time,
+// so exports can be streamed to any writer without buffering them.
+func (sf *Salesforce) QueryBulkExportTo(
+	query string,
+	w io.Writer,
+	opts ...BulkJobOption,
+) error {
+	return sf.QueryBulkExportToCtx(context.Background(), query, w, opts...)
+}
+
+// QueryBulkExportToCtx is like QueryBulkExportTo but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) QueryBulkExportToCtx(
+	ctx context.Context,
+	query string,
+	w io.Writer,
+	opts ...BulkJobOption,
+) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	if w == nil {
+		return errors.New("writer is required")
+	}
+	return doQueryBulkTo(ctx, sf, w, query, opts...)
+}
+
+func (sf *Salesforce) QueryBulkIterator(
+	query string,
+	opts ...BulkJobOption,
+) (ResumableIterator, error) {
+	return sf.QueryBulkIteratorCtx(context.Background(), query, opts...)
+}
+
+// QueryBulkIteratorCtx is like QueryBulkIterator but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) QueryBulkIteratorCtx(
+	ctx context.Context,
+	query string,
+	opts ...BulkJobOption,
+) (ResumableIterator, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	options, err := newBulkQueryOptions(query, opts)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx) // stopped by Close or once the results have been read
+	chunks, err := startBulkQuery(ctx, sf, options)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return newBulkJobQueryIterator(ctx, cancel, sf, chunks, options), nil
+}
+
+// ResumeQueryIterator continues iterating over the results of an existing query job from a locator
+// saved from ResumableIterator.Locator, e.g. before the process restarted. An empty locator starts
+// from the first page. The job is waited for if it has not completed yet. PK chunked queries cannot be
+// resumed, so WithPKChunking returns an error.
+func (sf *Salesforce) ResumeQueryIterator(
+	bulkJobId string,
+	locator string,
+	opts ...BulkJobOption,
+) (ResumableIterator, error) {
+	return sf.ResumeQueryIteratorCtx(context.Background(), bulkJobId, locator, opts...)
+}
+
+// ResumeQueryIteratorCtx is like ResumeQueryIterator but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) ResumeQueryIteratorCtx(
+	ctx context.Context,
+	bulkJobId string,
+	locator string,
+	opts ...BulkJobOption,
+) (ResumableIterator, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if bulkJobId == "" {
+		return nil, errors.New("bulk job id is required")
+	}
+	options, err := newBulkQueryOptions("", opts)
+	if err != nil {
+		return nil, err
+	}
+	if options.chunkSize > 0 {
+		return nil, errors.New("PK chunked queries cannot be resumed")
+	}
+	pollErr := waitForJobResults(ctx, sf, bulkJobId, queryJobType, sf.config.bulkPollInterval)
+	if pollErr != nil {
+		return nil, pollErr
+	}
+	chunk := newCompletedQueryChunk("", bulkJobId)
+	chunk.locator = locator
+	ctx, cancel := context.WithCancel(ctx)
+	return newBulkJobQueryIterator(ctx, cancel, sf, []*bulkQueryChunk{chunk}, options), nil
+}
+
+func (sf *Salesforce) InsertBulk(
+	sObjectName string,
+	records any,
+	batchSize int,
+	waitForResults bool,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.InsertBulkCtx(
+		context.Background(),
+		sObjectName,
+		records,
+		batchSize,
+		waitForResults,
+		opts...,
+	)
+}
+
+// InsertBulkCtx is like InsertBulk but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) InsertBulkCtx(
+	ctx context.Context,
+	sObjectName string,
+	records any,
+	batchSize int,
+	waitForResults bool,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.InsertBulkAssignCtx(ctx, sObjectName, records, batchSize, waitForResults, "", opts...)
+}
+
+// InsertBulkWithIds is like InsertBulk but takes a pointer to a slice of records, waits for the jobs
+// to complete and writes the Id of each created record into it.
+func (sf *Salesforce) InsertBulkWithIds(
+	sObjectName string,
+	records any,
+	batchSize int,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.InsertBulkWithIdsCtx(context.Background(), sObjectName, records, batchSize, opts...)
+}
+
+// InsertBulkWithIdsCtx is like InsertBulkWithIds but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) InsertBulkWithIdsCtx(
+	ctx context.Context,
+	sObjectName string,
+	records any,
+	batchSize int,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	if typErr := validateOfTypePointerToSlice(records); typErr != nil {
+		return nil, typErr
+	}
+	slice := reflect.ValueOf(records).Elem().Interface()
+	validationErr := validateBulk(*sf, slice, batchSize, false, sObjectName, "")
+	if validationErr != nil {
+		return nil, validationErr
+	}
+	format, formatErr := newBulkIngestOptions(opts)
+	if formatErr != nil {
+		return nil, formatErr
+	}
+
+	return doInsertBulkWithIds(ctx, sf, sObjectName, records, batchSize, format)
+}
+
+func (sf *Salesforce) InsertBulkAssign(
+	sObjectName string,
+	records any,
+	batchSize int,
+	waitForResults bool,
+	assignmentRuleId string,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.InsertBulkAssignCtx(
+		context.Background(),
+		sObjectName,
+		records,
+		batchSize,
+		waitForResults,
+		assignmentRuleId,
+		opts...,
+	)
+}
+
+// InsertBulkAssignCtx is like InsertBulkAssign but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) InsertBulkAssignCtx(
+	ctx context.Context,
+	sObjectName string,
+	records any,
+	batchSize int,
+	waitForResults bool,
+	assignmentRuleId string,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	validationErr := validateBulk(*sf, records, batchSize, false, sObjectName, assignmentRuleId)
+	if validationErr != nil {
+		return []string{}, validationErr
+	}
+	format, formatErr := newBulkIngestOptions(opts)
+	if formatErr != nil {
+		return []string{}, formatErr
+	}
+
+	jobIds, bulkErr := doBulkJob(
+		ctx,
+		sf,
+		sObjectName,
+		"",
+		insertOperation,
+		records,
+		batchSize,
+		waitForResults,
+		assignmentRuleId,
+		format,
+	)
+	if bulkErr != nil {
+		return jobIds, bulkErr
+	}
+
+	return jobIds, nil
+}
+
+func (sf *Salesforce) InsertBulkFile(
+	sObjectName string,
+	filePath string,
+	batchSize int,
+	waitForResults bool,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.InsertBulkFileCtx(
+		context.Background(),
+		sObjectName,
+		filePath,
+		batchSize,
+		waitForResults,
+		opts...,
+	)
+}
+
+// InsertBulkFileCtx is like InsertBulkFile but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) InsertBulkFileCtx(
+	ctx context.Context,
+	sObjectName string,
+	filePath string,
+	batchSize int,
+	waitForResults bool,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.InsertBulkFileAssignCtx(
+		ctx,
+		sObjectName,
+		filePath,
+		batchSize,
+		waitForResults,
+		"",
+		opts...,
+	)
+}
+
+func (sf *Salesforce) InsertBulkFileAssign(
+	sObjectName string,
+	filePath string,
+	batchSize int,
+	waitForResults bool,
+	assignmentRuleId string,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.InsertBulkFileAssignCtx(
+		context.Background(),
+		sObjectName,
+		filePath,
+		batchSize,
+		waitForResults,
+		assignmentRuleId,
+		opts...,
+	)
+}
+
+// InsertBulkFileAssignCtx is like InsertBulkFileAssign but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) InsertBulkFileAssignCtx(
+	ctx context.Context,
+	sObjectName string,
+	filePath string,
+	batchSize int,
+	waitForResults bool,
+	assignmentRuleId string,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	validationErr := validateBulk(*sf, nil, batchSize, true, sObjectName, assignmentRuleId)
+	if validationErr != nil {
+		return []string{}, validationErr
+	}
+	format, formatErr := newBulkIngestOptions(opts)
+	if formatErr != nil {
+		return []string{}, formatErr
+	}
+
+	jobIds, bulkErr := doBulkJobWithFile(
+		ctx,
+		sf,
+		sObjectName,
+		"",
+		insertOperation,
+		filePath,
+		batchSize,
+		waitForResults,
+		assignmentRuleId,
+		format,
+	)
+	if bulkErr != nil {
+		return jobIds, bulkErr
+	}
+
+	return jobIds, nil
+}
+
+func (sf *Salesforce) UpdateBulk(
+	sObjectName string,
+	records any,
+	batchSize int,
+	waitForResults bool,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.UpdateBulkCtx(
+		context.Background(),
+		sObjectName,
+		records,
+		batchSize,
+		waitForResults,
+		opts...,
+	)
+}
+
+// UpdateBulkCtx is like UpdateBulk but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) UpdateBulkCtx(
+	ctx context.Context,
+	sObjectName string,
+	records any,
+	batchSize int,
+	waitForResults bool,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.UpdateBulkAssignCtx(ctx, sObjectName, records, batchSize, waitForResults, "", opts...)
+}
+
+func (sf *Salesforce) UpdateBulkAssign(
+	sObjectName string,
+	records any,
+	batchSize int,
+	waitForResults bool,
+	assignmentRuleId string,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.UpdateBulkAssignCtx(
+		context.Background(),
+		sObjectName,
+		records,
+		batchSize,
+		waitForResults,
+		assignmentRuleId,
+		opts...,
+	)
+}
+
+// UpdateBulkAssignCtx is like UpdateBulkAssign but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) UpdateBulkAssignCtx(
+	ctx context.Context,
+	sObjectName string,
+	records any,
+	batchSize int,
+	waitForResults bool,
+	assignmentRuleId string,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	validationErr := validateBulk(*sf, records, batchSize, false, sObjectName, assignmentRuleId)
+	if validationErr != nil {
+		return []string{}, validationErr
+	}
+	format, formatErr := newBulkIngestOptions(opts)
+	if formatErr != nil {
+		return []string{}, formatErr
+	}
+
+	jobIds, bulkErr := doBulkJob(
+		ctx,
+		sf,
+		sObjectName,
+		"",
+		updateOperation,
+		records,
+		batchSize,
+		waitForResults,
+		assignmentRuleId,
+		format,
+	)
+	if bulkErr != nil {
+		return jobIds, bulkErr
+	}
+
+	return jobIds, nil
+}
+
+func (sf *Salesforce) UpdateBulkFile(
+	sObjectName string,
+	filePath string,
+	batchSize int,
+	waitForResults bool,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.UpdateBulkFileCtx(
+		context.Background(),
+		sObjectName,
+		filePath,
+		batchSize,
+		waitForResults,
+		opts...,
+	)
+}
+
+// UpdateBulkFileCtx is like UpdateBulkFile but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) UpdateBulkFileCtx(
+	ctx context.Context,
+	sObjectName string,
+	filePath string,
+	batchSize int,
+	waitForResults bool,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.UpdateBulkFileAssignCtx(
+		ctx,
+		sObjectName,
+		filePath,
+		batchSize,
+		waitForResults,
+		"",
+		opts...,
+	)
+}
+
+func (sf *Salesforce) UpdateBulkFileAssign(
+	sObjectName string,
+	filePath string,
+	batchSize int,
+	waitForResults bool,
+	assignmentRuleId string,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.UpdateBulkFileAssignCtx(
+		context.Background(),
+		sObjectName,
+		filePath,
+		batchSize,
+		waitForResults,
+		assignmentRuleId,
+		opts...,
+	)
+}
+
+// UpdateBulkFileAssignCtx is like UpdateBulkFileAssign but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) UpdateBulkFileAssignCtx(
+	ctx context.Context,
+	sObjectName string,
+	filePath string,
+	batchSize int,
+	waitForResults bool,
+	assignmentRuleId string,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	validationErr := validateBulk(*sf, nil, batchSize, true, sObjectName, assignmentRuleId)
+	if validationErr != nil {
+		return []string{}, validationErr
+	}
+	format, formatErr := newBulkIngestOptions(opts)
+	if formatErr != nil {
+		return []string{}, formatErr
+	}
+
+	jobIds, bulkErr := doBulkJobWithFile(
+		ctx,
+		sf,
+		sObjectName,
+		"",
+		updateOperation,
+		filePath,
+		batchSize,
+		waitForResults,
+		assignmentRuleId,
+		format,
+	)
+	if bulkErr != nil {
+		return jobIds, bulkErr
+	}
+
+	return jobIds, nil
+}
+
+func (sf *Salesforce) UpsertBulk(
+	sObjectName string,
+	externalIdFieldName string,
+	records any,
+	batchSize int,
+	waitForResults bool,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.UpsertBulkCtx(
+		context.Background(),
+		sObjectName,
+		externalIdFieldName,
+		records,
+		batchSize,
+		waitForResults,
+		opts...,
+	)
+}
+
+// UpsertBulkCtx is like UpsertBulk but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) UpsertBulkCtx(
+	ctx context.Context,
+	sObjectName string,
+	externalIdFieldName string,
+	records any,
+	batchSize int,
+	waitForResults bool,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.UpsertBulkAssignCtx(
+		ctx,
+		sObjectName,
+		externalIdFieldName,
+		records,
+		batchSize,
+		waitForResults,
+		"",
+		opts...,
+	)
+}
+
+func (sf *Salesforce) UpsertBulkAssign(
+	sObjectName string,
+	externalIdFieldName string,
+	records any,
+	batchSize int,
+	waitForResults bool,
+	assignmentRuleId string,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.UpsertBulkAssignCtx(
+		context.Background(),
+		sObjectName,
+		externalIdFieldName,
+		records,
+		batchSize,
+		waitForResults,
+		assignmentRuleId,
+		opts...,
+	)
+}
+
+// UpsertBulkAssignCtx is like UpsertBulkAssign but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) UpsertBulkAssignCtx(
+	ctx context.Context,
+	sObjectName string,
+	externalIdFieldName string,
+	records any,
+	batchSize int,
+	waitForResults bool,
+	assignmentRuleId string,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	validationErr := validateBulk(*sf, records, batchSize, false, sObjectName, assignmentRuleId)
+	if validationErr != nil {
+		return []string{}, validationErr
+	}
+	format, formatErr := newBulkIngestOptions(opts)
+	if formatErr != nil {
+		return []string{}, formatErr
+	}
+
+	jobIds, bulkErr := doBulkJob(
+		ctx,
+		sf,
+		sObjectName,
+		externalIdFieldName,
+		upsertOperation,
+		records,
+		batchSize,
+		waitForResults,
+		assignmentRuleId,
+		format,
+	)
+	if bulkErr != nil {
+		return jobIds, bulkErr
+	}
+
+	return jobIds, nil
+}
+
+func (sf *Salesforce) UpsertBulkFile(
+	sObjectName string,
+	externalIdFieldName string,
+	filePath string,
+	batchSize int,
+	waitForResults bool,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.UpsertBulkFileCtx(
+		context.Background(),
+		sObjectName,
+		externalIdFieldName,
+		filePath,
+		batchSize,
+		waitForResults,
+		opts...,
+	)
+}
+
+// UpsertBulkFileCtx is like UpsertBulkFile but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) UpsertBulkFileCtx(
+	ctx context.Context,
+	sObjectName string,
+	externalIdFieldName string,
+	filePath string,
+	batchSize int,
+	waitForResults bool,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.UpsertBulkFileAssignCtx(
+		ctx,
+		sObjectName,
+		externalIdFieldName,
+		filePath,
+		batchSize,
+		waitForResults,
+		"",
+		opts...,
+	)
+}
+
+func (sf *Salesforce) UpsertBulkFileAssign(
+	sObjectName string,
+	externalIdFieldName string,
+	filePath string,
+	batchSize int,
+	waitForResults bool,
+	assignmentRuleId string,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.UpsertBulkFileAssignCtx(
+		context.Background(),
+		sObjectName,
+		externalIdFieldName,
+		filePath,
+		batchSize,
+		waitForResults,
+		assignmentRuleId,
+		opts...,
+	)
+}
+
+// UpsertBulkFileAssignCtx is like UpsertBulkFileAssign but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) UpsertBulkFileAssignCtx(
+	ctx context.Context,
+	sObjectName string,
+	externalIdFieldName string,
+	filePath string,
+	batchSize int,
+	waitForResults bool,
+	assignmentRuleId string,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	validationErr := validateBulk(*sf, nil, batchSize, true, sObjectName, assignmentRuleId)
+	if validationErr != nil {
+		return []string{}, validationErr
 	}
-	body, jsonErr := json.Marshal(queryJobReq)
-	if jsonErr != nil {
-		return nil, jsonErr
+	format, formatErr := newBulkIngestOptions(opts)
+	if formatErr != nil {
+		return []string{}, formatErr
 	}
 
-	job, jobCreationErr := createBulkJob(sf, queryJobType, body)
-	if jobCreationErr != nil {
-		return nil, jobCreationErr
-	}
-	if job.Id == "" {
-		newErr := errors.New("error creating bulk query job")
-		return nil, newErr
+	jobIds, bulkErr := doBulkJobWithFile(
+		ctx,
+		sf,
+		sObjectName,
+		externalIdFieldName,
+		upsertOperation,
+		filePath,
+		batchSize,
+		waitForResults,
+		assignmentRuleId,
+		format,
+	)
+	if bulkErr != nil {
+		return jobIds, bulkErr
 	}
-	return newBulkJobQueryIterator(sf, job.Id)
+
+	return jobIds, nil
 }
 
-func (sf *Salesforce) InsertBulk(
+func (sf *Salesforce) DeleteBulk(
 	sObjectName string,
 	records any,
 	batchSize int,
 	waitForResults bool,
+	opts ...BulkJobOption,
 ) ([]string, error) {
-	return sf.InsertBulkAssign(sObjectName, records, batchSize, waitForResults, "")
+	return sf.DeleteBulkCtx(
+		context.Background(),
+		sObjectName,
+		records,
+		batchSize,
+		waitForResults,
+		opts...,
+	)
 }
 
-func (sf *Salesforce) InsertBulkAssign(
+// DeleteBulkCtx is like DeleteBulk but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) DeleteBulkCtx(
+	ctx context.Context,
 	sObjectName string,
 	records any,
 	batchSize int,
 	waitForResults bool,
-	assignmentRuleId string,
+	opts ...BulkJobOption,
 ) ([]string, error) {
-	validationErr := validateBulk(*sf, records, batchSize, false, sObjectName, assignmentRuleId)
+	validationErr := validateBulk(*sf, records, batchSize, false, sObjectName, "")
 	if validationErr != nil {
 		return []string{}, validationErr
 	}
+	format, formatErr := newBulkIngestOptions(opts)
+	if formatErr != nil {
+		return []string{}, formatErr
+	}
 
 	jobIds, bulkErr := doBulkJob(
+		ctx,
 		sf,
 		sObjectName,
 		"",
-		insertOperation,
+		deleteOperation,
 		records,
 		batchSize,
 		waitForResults,
-		assignmentRuleId,
+		"",
+		format,
 	)
 	if bulkErr != nil {
-		return []string{}, bulkErr
+		return jobIds, bulkErr
 	}
 
 	return jobIds, nil
 }
 
-func (sf *Salesforce) InsertBulkFile(
+func (sf *Salesforce) DeleteBulkFile(
 	sObjectName string,
 	filePath string,
 	batchSize int,
 	waitForResults bool,
+	opts ...BulkJobOption,
 ) ([]string, error) {
-	return sf.InsertBulkFileAssign(sObjectName, filePath, batchSize, waitForResults, "")
+	return sf.DeleteBulkFileCtx(
+		context.Background(),
+		sObjectName,
+		filePath,
+		batchSize,
+		waitForResults,
+		opts...,
+	)
 }
 
-func (sf *Salesforce) InsertBulkFileAssign(
+// DeleteBulkFileCtx is like DeleteBulkFile but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) DeleteBulkFileCtx(
+	ctx context.Context,
 	sObjectName string,
 	filePath string,
 	batchSize int,
 	waitForResults bool,
-	assignmentRuleId string,
+	opts ...BulkJobOption,
 ) ([]string, error) {
-	validationErr := validateBulk(*sf, nil, batchSize, true, sObjectName, assignmentRuleId)
+	validationErr := validateBulk(*sf, nil, batchSize, true, sObjectName, "")
 	if validationErr != nil {
 		return []string{}, validationErr
 	}
+	format, formatErr := newBulkIngestOptions(opts)
+	if formatErr != nil {
+		return []string{}, formatErr
+	}
 
 	jobIds, bulkErr := doBulkJobWithFile(
+		ctx,
 		sf,
 		sObjectName,
 		"",
-		insertOperation,
+		deleteOperation,
 		filePath,
 		batchSize,
 		waitForResults,
-		assignmentRuleId,
+		"",
+		format,
 	)
 	if bulkErr != nil {
-		return []string{}, bulkErr
+		return jobIds, bulkErr
 	}
 
 	return jobIds, nil
 }
 
-func (sf *Salesforce) UpdateBulk(
+// HardDeleteBulk permanently deletes the records using Bulk API v2, bypassing the Recycle Bin,
+// and returns a list of Job IDs. The user needs the "Bulk API Hard Delete" permission.
+func (sf *Salesforce) HardDeleteBulk(
 	sObjectName string,
 	records any,
 	batchSize int,
 	waitForResults bool,
+	opts ...BulkJobOption,
 ) ([]string, error) {
-	return sf.UpdateBulkAssign(sObjectName, records, batchSize, waitForResults, "")
+	return sf.HardDeleteBulkCtx(
+		context.Background(),
+		sObjectName,
+		records,
+		batchSize,
+		waitForResults,
+		opts...,
+	)
 }
 
-func (sf *Salesforce) UpdateBulkAssign(
+// HardDeleteBulkCtx is like HardDeleteBulk but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) HardDeleteBulkCtx(
+	ctx context.Context,
 	sObjectName string,
 	records any,
 	batchSize int,
 	waitForResults bool,
-	assignmentRuleId string,
+	opts ...BulkJobOption,
 ) ([]string, error) {
-	validationErr := validateBulk(*sf, records, batchSize, false, sObjectName, assignmentRuleId)
+	validationErr := validateBulk(*sf, records, batchSize, false, sObjectName, "")
 	if validationErr != nil {
 		return []string{}, validationErr
 	}
+	format, formatErr := newBulkIngestOptions(opts)
+	if formatErr != nil {
+		return []string{}, formatErr
+	}
 
 	jobIds, bulkErr := doBulkJob(
+		ctx,
 		sf,
 		sObjectName,
 		"",
-		updateOperation,
+		hardDeleteOperation,
 		records,
 		batchSize,
 		waitForResults,
-		assignmentRuleId,
+		"",
+		format,
 	)
 	if bulkErr != nil {
-		return []string{}, bulkErr
+		return jobIds, bulkErr
 	}
 
 	return jobIds, nil
 }
 
-func (sf *Salesforce) UpdateBulkFile(
+// HardDeleteBulkFile is like HardDeleteBulk but reads the records to delete from a CSV file.
+func (sf *Salesforce) HardDeleteBulkFile(
 	sObjectName string,
 	filePath string,
 	batchSize int,
 	waitForResults bool,
+	opts ...BulkJobOption,
 ) ([]string, error) {
-	return sf.UpdateBulkFileAssign(sObjectName, filePath, batchSize, waitForResults, "")
+	return sf.HardDeleteBulkFileCtx(
+		context.Background(),
+		sObjectName,
+		filePath,
+		batchSize,
+		waitForResults,
+		opts...,
+	)
 }
 
-func (sf *Salesforce) UpdateBulkFileAssign(
+// HardDeleteBulkFileCtx is like HardDeleteBulkFile but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) HardDeleteBulkFileCtx(
+	ctx context.Context,
 	sObjectName string,
 	filePath string,
 	batchSize int,
 	waitForResults bool,
-	assignmentRuleId string,
+	opts ...BulkJobOption,
 ) ([]string, error) {
-	validationErr := validateBulk(*sf, nil, batchSize, true, sObjectName, assignmentRuleId)
+	validationErr := validateBulk(*sf, nil, batchSize, true, sObjectName, "")
 	if validationErr != nil {
 		return []string{}, validationErr
 	}
+	format, formatErr := newBulkIngestOptions(opts)
+	if formatErr != nil {
+		return []string{}, formatErr
+	}
 
 	jobIds, bulkErr := doBulkJobWithFile(
+		ctx,
 		sf,
 		sObjectName,
 		"",
-		updateOperation,
+		hardDeleteOperation,
 		filePath,
 		batchSize,
 		waitForResults,
-		assignmentRuleId,
+		"",
+		format,
 	)
 	if bulkErr != nil {
-		return []string{}, bulkErr
+		return jobIds, bulkErr
 	}
 
 	return jobIds, nil
 }
 
-func (sf *Salesforce) UpsertBulk(
+// InsertBulkReader inserts the records in CSV data read from reader using Bulk API v2,
+// returning a list of Job IDs. The data is streamed and split into jobs
+// of up to batchSize rows and the Bulk API upload size limit.
+func (sf *Salesforce) InsertBulkReader(
 	sObjectName string,
-	externalIdFieldName string,
-	records any,
+	reader io.Reader,
 	batchSize int,
 	waitForResults bool,
+	opts ...BulkJobOption,
 ) ([]string, error) {
-	return sf.UpsertBulkAssign(
+	return sf.InsertBulkReaderCtx(
+		context.Background(),
 		sObjectName,
-		externalIdFieldName,
-		records,
+		reader,
 		batchSize,
 		waitForResults,
-		"",
+		opts...,
 	)
 }
 
-func (sf *Salesforce) UpsertBulkAssign(
+// InsertBulkReaderCtx is like InsertBulkReader but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) InsertBulkReaderCtx(
+	ctx context.Context,
 	sObjectName string,
-	externalIdFieldName string,
-	records any,
+	reader io.Reader,
 	batchSize int,
 	waitForResults bool,
-	assignmentRuleId string,
+	opts ...BulkJobOption,
 ) ([]string, error) {
-	validationErr := validateBulk(*sf, records, batchSize, false, sObjectName, assignmentRuleId)
+	validationErr := validateBulk(*sf, nil, batchSize, true, sObjectName, "")
 	if validationErr != nil {
 		return []string{}, validationErr
 	}
+	format, formatErr := newBulkIngestOptions(opts)
+	if formatErr != nil {
+		return []string{}, formatErr
+	}
 
-	jobIds, bulkErr := doBulkJob(
+	return doBulkJobWithReader(
+		ctx,
 		sf,
 		sObjectName,
-		externalIdFieldName,
-		upsertOperation,
-		records,
+		"",
+		insertOperation,
+		reader,
 		batchSize,
 		waitForResults,
-		assignmentRuleId,
+		"",
+		format,
 	)
-	if bulkErr != nil {
-		return []string{}, bulkErr
-	}
-
-	return jobIds, nil
 }
 
-func (sf *Salesforce) UpsertBulkFile(
+// UpdateBulkReader updates the records in CSV data read from reader using Bulk API v2,
+// returning a list of Job IDs. The data is streamed and split into jobs
+// of up to batchSize rows and the Bulk API upload size limit.
+func (sf *Salesforce) UpdateBulkReader(
 	sObjectName string,
-	externalIdFieldName string,
-	filePath string,
+	reader io.Reader,
 	batchSize int,
 	waitForResults bool,
+	opts ...BulkJobOption,
 ) ([]string, error) {
-	return sf.UpsertBulkFileAssign(
+	return sf.UpdateBulkReaderCtx(
+		context.Background(),
 		sObjectName,
-		externalIdFieldName,
-		filePath,
+		reader,
 		batchSize,
 		waitForResults,
-		"",
+		opts...,
 	)
 }
 
-func (sf *Salesforce) UpsertBulkFileAssign(
+// UpdateBulkReaderCtx is like UpdateBulkReader but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) UpdateBulkReaderCtx(
+	ctx context.Context,
 	sObjectName string,
-	externalIdFieldName string,
-	filePath string,
+	reader io.Reader,
 	batchSize int,
 	waitForResults bool,
-	assignmentRuleId string,
+	opts ...BulkJobOption,
 ) ([]string, error) {
-	validationErr := validateBulk(*sf, nil, batchSize, true, sObjectName, assignmentRuleId)
+	validationErr := validateBulk(*sf, nil, batchSize, true, sObjectName, "")
 	if validationErr != nil {
 		return []string{}, validationErr
 	}
+	format, formatErr := newBulkIngestOptions(opts)
+	if formatErr != nil {
+		return []string{}, formatErr
+	}
 
-	jobIds, bulkErr := doBulkJobWithFile(
+	return doBulkJobWithReader(
+		ctx,
 		sf,
 		sObjectName,
-		externalIdFieldName,
-		upsertOperation,
-		filePath,
+		"",
+		updateOperation,
+		reader,
 		batchSize,
 		waitForResults,
-		assignmentRuleId,
+		"",
+		format,
 	)
-	if bulkErr != nil {
-		return []string{}, bulkErr
-	}
+}
 
-	return jobIds, nil
+// UpsertBulkReader upserts the records in CSV data read from reader using Bulk API v2,
+// returning a list of Job IDs. The data is streamed and split into jobs
+// of up to batchSize rows and the Bulk API upload size limit.
+func (sf *Salesforce) UpsertBulkReader(
+	sObjectName string,
+	externalIdFieldName string,
+	reader io.Reader,
+	batchSize int,
+	waitForResults bool,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.UpsertBulkReaderCtx(
+		context.Background(),
+		sObjectName,
+		externalIdFieldName,
+		reader,
+		batchSize,
+		waitForResults,
+		opts...,
+	)
 }
 
-func (sf *Salesforce) DeleteBulk(
+// UpsertBulkReaderCtx is like UpsertBulkReader but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) UpsertBulkReaderCtx(
+	ctx context.Context,
 	sObjectName string,
-	records any,
+	externalIdFieldName string,
+	reader io.Reader,
 	batchSize int,
 	waitForResults bool,
+	opts ...BulkJobOption,
 ) ([]string, error) {
-	validationErr := validateBulk(*sf, records, batchSize, false, sObjectName, "")
+	validationErr := validateBulk(*sf, nil, batchSize, true, sObjectName, "")
 	if validationErr != nil {
 		return []string{}, validationErr
 	}
+	format, formatErr := newBulkIngestOptions(opts)
+	if formatErr != nil {
+		return []string{}, formatErr
+	}
 
-	jobIds, bulkErr := doBulkJob(
+	return doBulkJobWithReader(
+		ctx,
 		sf,
 		sObjectName,
-		"",
-		deleteOperation,
-		records,
+		externalIdFieldName,
+		upsertOperation,
+		reader,
 		batchSize,
 		waitForResults,
 		"",
+		format,
 	)
-	if bulkErr != nil {
-		return []string{}, bulkErr
-	}
+}
 
-	return jobIds, nil
+// DeleteBulkReader deletes the records in CSV data read from reader using Bulk API v2,
+// returning a list of Job IDs. The data is streamed and split into jobs
+// of up to batchSize rows and the Bulk API upload size limit.
+func (sf *Salesforce) DeleteBulkReader(
+	sObjectName string,
+	reader io.Reader,
+	batchSize int,
+	waitForResults bool,
+	opts ...BulkJobOption,
+) ([]string, error) {
+	return sf.DeleteBulkReaderCtx(
+		context.Background(),
+		sObjectName,
+		reader,
+		batchSize,
+		waitForResults,
+		opts...,
+	)
 }
 
-func (sf *Salesforce) DeleteBulkFile(
+// DeleteBulkReaderCtx is like DeleteBulkReader but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) DeleteBulkReaderCtx(
+	ctx context.Context,
 	sObjectName string,
-	filePath string,
+	reader io.Reader,
 	batchSize int,
 	waitForResults bool,
+	opts ...BulkJobOption,
 ) ([]string, error) {
 	validationErr := validateBulk(*sf, nil, batchSize, true, sObjectName, "")
 	if validationErr != nil {
 		return []string{}, validationErr
 	}
+	format, formatErr := newBulkIngestOptions(opts)
+	if formatErr != nil {
+		return []string{}, formatErr
+	}
 
-	jobIds, bulkErr := doBulkJobWithFile(
+	return doBulkJobWithReader(
+		ctx,
 		sf,
 		sObjectName,
 		"",
 		deleteOperation,
-		filePath,
+		reader,
 		batchSize,
 		waitForResults,
 		"",
+		format,
 	)
-	if bulkErr != nil {
-		return []string{}, bulkErr
-	}
-
-	return jobIds, nil
 }
 
 func (sf *Salesforce) GetJobResults(bulkJobId string) (BulkJobResults, error) {
+	return sf.GetJobResultsCtx(context.Background(), bulkJobId)
+}
+
+// GetJobResultsCtx is like GetJobResults but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) GetJobResultsCtx(
+	ctx context.Context,
+	bulkJobId string,
+) (BulkJobResults, error) {
 	authErr := validateAuth(*sf)
 	if authErr != nil {
 		return BulkJobResults{}, authErr
 	}
 
-	job, err := getJobResults(sf, ingestJobType, bulkJobId)
+	job, err := getJobResults(ctx, sf, ingestJobType, bulkJobId)
 	if err != nil {
 		return BulkJobResults{}, err
 	}
 
-	if job.State == jobStateJobComplete {
-		job, err = getJobRecordResults(sf, job)
+	// failed and aborted jobs keep the records processed before they stopped and list the rest as unprocessed
+	if isBulkJobFinished(job.State) {
+		job, err = getJobRecordResults(ctx, sf, job)
 		if err != nil {
 			return job, err
 		}
@@ -852,6 +2279,164 @@ func (sf *Salesforce) GetJobResults(bulkJobId string) (BulkJobResults, error) {
 	return job, nil
 }
 
+// WaitForBulkJobs waits for all of the ingest jobs to finish and returns their results in the same order.
+// The errors of failed jobs are joined together and identify the job.
+func (sf *Salesforce) WaitForBulkJobs(jobIds []string) ([]BulkJobResults, error) {
+	return sf.WaitForBulkJobsCtx(context.Background(), jobIds)
+}
+
+// WaitForBulkJobsCtx is like WaitForBulkJobs but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) WaitForBulkJobsCtx(
+	ctx context.Context,
+	jobIds []string,
+) ([]BulkJobResults, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	jobErrors := waitForBulkJobs(ctx, sf, jobIds, ingestJobType)
+	results := make([]BulkJobResults, 0, len(jobIds))
+	for _, id := range jobIds {
+		job, err := sf.GetJobResultsCtx(ctx, id)
+		if err != nil {
+			jobErrors = errors.Join(jobErrors, fmt.Errorf("bulk job %s: %w", id, err))
+		}
+		results = append(results, job)
+	}
+
+	return results, jobErrors
+}
+
+// AttachBulkIngest re-attaches to ingest jobs created earlier, e.g. before the process restarted,
+// waits for them to finish and returns their results in the same order. Jobs that are still open
+// may not have all of their data uploaded, so they are not waited for and are reported as errors.
+func (sf *Salesforce) AttachBulkIngest(jobIds []string) ([]BulkJobResults, error) {
+	return sf.AttachBulkIngestCtx(context.Background(), jobIds)
+}
+
+// AttachBulkIngestCtx is like AttachBulkIngest but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) AttachBulkIngestCtx(
+	ctx context.Context,
+	jobIds []string,
+) ([]BulkJobResults, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	var jobErrors error
+	results := make([]BulkJobResults, len(jobIds))
+	attached := make([]string, 0, len(jobIds))
+	for i, id := range jobIds {
+		var err error
+		if id == "" {
+			err = errors.New("bulk job id is required")
+		} else {
+			results[i], err = getJobResults(ctx, sf, ingestJobType, id)
+		}
+		if err == nil && results[i].State == jobStateOpen {
+			err = errors.New("job is still open and its data may not have been uploaded completely")
+		}
+		if err != nil {
+			jobErrors = errors.Join(jobErrors, fmt.Errorf("bulk job %s: %w", id, err))
+			continue
+		}
+		attached = append(attached, id)
+	}
+
+	jobErrors = errors.Join(jobErrors, waitForBulkJobs(ctx, sf, attached, ingestJobType))
+	for i, id := range jobIds {
+		if !slices.Contains(attached, id) {
+			continue
+		}
+		job, err := sf.GetJobResultsCtx(ctx, id)
+		if err != nil {
+			jobErrors = errors.Join(jobErrors, fmt.Errorf("bulk job %s: %w", id, err))
+		}
+		results[i] = job
+	}
+
+	return results, jobErrors
+}
+
+// ListBulkJobs returns the bulk ingest or query jobs that match filter, reading every page of results.
+func (sf *Salesforce) ListBulkJobs(filter BulkJobFilter) ([]BulkJobInfo, error) {
+	return sf.ListBulkJobsCtx(context.Background(), filter)
+}
+
+// ListBulkJobsCtx is like ListBulkJobs but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) ListBulkJobsCtx(
+	ctx context.Context,
+	filter BulkJobFilter,
+) ([]BulkJobInfo, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	return doListBulkJobs(ctx, sf, filter)
+}
+
+// GetBulkJob returns the information of a bulk ingest or query job.
+func (sf *Salesforce) GetBulkJob(bulkJobId string, jobType BulkJobType) (BulkJobInfo, error) {
+	return sf.GetBulkJobCtx(context.Background(), bulkJobId, jobType)
+}
+
+// GetBulkJobCtx is like GetBulkJob but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) GetBulkJobCtx(
+	ctx context.Context,
+	bulkJobId string,
+	jobType BulkJobType,
+) (BulkJobInfo, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return BulkJobInfo{}, authErr
+	}
+	return doBulkJobRequest(ctx, sf, http.MethodGet, jobType, bulkJobId, "")
+}
+
+// AbortBulkJob aborts a bulk ingest or query job, returning the updated job information.
+func (sf *Salesforce) AbortBulkJob(bulkJobId string, jobType BulkJobType) (BulkJobInfo, error) {
+	return sf.AbortBulkJobCtx(context.Background(), bulkJobId, jobType)
+}
+
+// AbortBulkJobCtx is like AbortBulkJob but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) AbortBulkJobCtx(
+	ctx context.Context,
+	bulkJobId string,
+	jobType BulkJobType,
+) (BulkJobInfo, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return BulkJobInfo{}, authErr
+	}
+	body, err := json.Marshal(map[string]string{"state": jobStateAborted})
+	if err != nil {
+		return BulkJobInfo{}, err
+	}
+	return doBulkJobRequest(ctx, sf, http.MethodPatch, jobType, bulkJobId, string(body))
+}
+
+// DeleteBulkJob deletes a bulk ingest or query job along with its data and results.
+// The job must be completed, failed or aborted.
+func (sf *Salesforce) DeleteBulkJob(bulkJobId string, jobType BulkJobType) error {
+	return sf.DeleteBulkJobCtx(context.Background(), bulkJobId, jobType)
+}
+
+// DeleteBulkJobCtx is like DeleteBulkJob but uses ctx for the underlying HTTP requests.
+func (sf *Salesforce) DeleteBulkJobCtx(
+	ctx context.Context,
+	bulkJobId string,
+	jobType BulkJobType,
+) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	_, err := doBulkJobRequest(ctx, sf, http.MethodDelete, jobType, bulkJobId, "")
+	return err
+}
+
 // GetAuthFlow returns the authentication flow type used
 func (sf *Salesforce) GetAuthFlow() AuthFlowType {
 	return sf.AuthFlow
@@ -886,12 +2471,12 @@ func (sf *Salesforce) GetAccessToken() string {
 	if sf.auth == nil {
 		return ""
 	}
-	return sf.auth.AccessToken
+	return sf.auth.accessToken()
 }
 
 func (sf *Salesforce) GetInstanceUrl() string {
 	if sf.auth == nil {
 		return ""
 	}
-	return sf.auth.InstanceUrl
+	return sf.auth.instanceUrl()
 }
@@ -3,13 +3,19 @@ package salesforce
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/spf13/afero"
@@ -67,11 +73,7 @@ func buildSalesforceStruct(auth *authentication) *Salesforce {
 	config := &configuration{}
 	config.setDefaults()
 	config.configureHttpClient()
-	return &Salesforce{
-		auth:     auth,
-		config:   config,
-		AuthFlow: AuthFlowAccessToken,
-	}
+	return newSalesforce(auth, config, AuthFlowAccessToken)
 }
 
 func Test_validateOfTypeSlice(t *testing.T) {
@@ -242,6 +244,7 @@ func TestInit(t *testing.T) {
 		IssuedAt:    "01/01/1970",
 		Signature:   "signed",
 		grantType:   grantTypeUsernamePassword,
+		session:     &session{},
 	}
 	serverUsernamePassword, _ := setupTestServer(sfAuthUsernamePassword, http.StatusOK)
 	defer serverUsernamePassword.Close()
@@ -262,6 +265,7 @@ func TestInit(t *testing.T) {
 		IssuedAt:    "01/01/1970",
 		Signature:   "signed",
 		grantType:   grantTypeClientCredentials,
+		session:     &session{},
 	}
 	serverClientCredentials, _ := setupTestServer(sfAuthClientCredentials, http.StatusOK)
 	defer serverClientCredentials.Close()
@@ -272,6 +276,24 @@ func TestInit(t *testing.T) {
 	}
 	sfAuthClientCredentials.creds = credsClientCredentials
 
+	sfAuthRefreshToken := authentication{
+		AccessToken: "1234",
+		InstanceUrl: "example.com",
+		Id:          "123abc",
+		IssuedAt:    "01/01/1970",
+		Signature:   "signed",
+		grantType:   grantTypeRefreshToken,
+		session:     &session{},
+	}
+	serverRefreshToken, _ := setupTestServer(sfAuthRefreshToken, http.StatusOK)
+	defer serverRefreshToken.Close()
+	credsRefreshToken := Creds{
+		Domain:       serverRefreshToken.URL,
+		ConsumerKey:  "key",
+		RefreshToken: "refresh",
+	}
+	sfAuthRefreshToken.creds = credsRefreshToken
+
 	sfAuthAccessToken := authentication{
 		AccessToken: "1234",
 		InstanceUrl: "example.com",
@@ -334,6 +356,12 @@ func TestInit(t *testing.T) {
 			want:    buildSalesforceStruct(&sfAuthClientCredentials),
 			wantErr: false,
 		},
+		{
+			name:    "authentication_refresh_token",
+			args:    args{creds: credsRefreshToken},
+			want:    buildSalesforceStruct(&sfAuthRefreshToken),
+			wantErr: false,
+		},
 		{
 			name:    "authentication_access_token",
 			args:    args{creds: credsAccessToken},
@@ -362,6 +390,53 @@ func TestInit(t *testing.T) {
 	}
 }
 
+func TestInit_expiredAccessTokenWithRefreshToken(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/services/oauth2/token" {
+			if r.FormValue("grant_type") != grantTypeRefreshToken ||
+				r.FormValue("refresh_token") != "r" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body, _ := json.Marshal(map[string]string{
+				"access_token": "refreshed",
+				"instance_url": server.URL,
+			})
+			if _, err := w.Write(body); err != nil {
+				t.Fatal(err.Error())
+			}
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer refreshed" {
+			w.WriteHeader(http.StatusUnauthorized)
+			body, _ := json.Marshal([]SalesforceErrorMessage{{ErrorCode: invalidSessionIdError}})
+			if _, err := w.Write(body); err != nil {
+				t.Fatal(err.Error())
+			}
+			return
+		}
+		if _, err := w.Write([]byte("{}")); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+
+	sf, err := Init(Creds{
+		Domain:         server.URL,
+		ConsumerKey:    "key",
+		ConsumerSecret: "secret",
+		AccessToken:    "expired",
+		RefreshToken:   "r",
+	})
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if sf.AuthFlow != AuthFlowAccessToken || sf.GetAccessToken() != "refreshed" {
+		t.Errorf("Init() flow = %v, access token = %v", sf.AuthFlow, sf.GetAccessToken())
+	}
+}
+
 func Test_validateSingles(t *testing.T) {
 	type account struct{}
 
@@ -791,6 +866,62 @@ func TestSalesforce_Query(t *testing.T) {
 	}
 }
 
+func TestSalesforce_QueryCtx(t *testing.T) {
+	type account struct {
+		Id   string
+		Name string
+	}
+	resp := queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records: []map[string]any{{
+			"Id":   "123abc",
+			"Name": "test account",
+		}},
+	}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		want    []account
+		wantErr error
+	}{
+		{
+			name: "successful_query",
+			ctx:  context.Background(),
+			want: []account{{
+				Id:   "123abc",
+				Name: "test account",
+			}},
+			wantErr: nil,
+		},
+		{
+			name:    "cancelled_context",
+			ctx:     cancelledCtx,
+			want:    []account{},
+			wantErr: context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := buildSalesforceStruct(&sfAuth)
+			got := []account{}
+			err := sf.QueryCtx(tt.ctx, "SELECT Id FROM Account", &got)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Salesforce.QueryCtx() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Salesforce.QueryCtx() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSalesforce_QueryStruct(t *testing.T) {
 	type account struct {
 		Id   string
@@ -862,6 +993,81 @@ func TestSalesforce_QueryStruct(t *testing.T) {
 	}
 }
 
+func TestSalesforce_QueryAll(t *testing.T) {
+	type account struct {
+		Id        string
+		IsDeleted bool
+	}
+	resp := queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records: []map[string]any{{
+			"Id":        "123abc",
+			"IsDeleted": true,
+		}},
+	}
+	server, sfAuth, capturedRequest := setupTestServerWithCapture(resp, http.StatusOK)
+	defer server.Close()
+
+	type fields struct {
+		auth *authentication
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		query   func(sf *Salesforce, sObject *[]account) error
+		want    []account
+		wantErr bool
+	}{
+		{
+			name:   "validation_fail",
+			fields: fields{auth: nil},
+			query: func(sf *Salesforce, sObject *[]account) error {
+				return sf.QueryAll("SELECT Id, IsDeleted FROM Account", sObject)
+			},
+			want:    []account{},
+			wantErr: true,
+		},
+		{
+			name:   "query_all",
+			fields: fields{auth: &sfAuth},
+			query: func(sf *Salesforce, sObject *[]account) error {
+				return sf.QueryAll("SELECT Id, IsDeleted FROM Account", sObject)
+			},
+			want:    []account{{Id: "123abc", IsDeleted: true}},
+			wantErr: false,
+		},
+		{
+			name:   "query_struct_all",
+			fields: fields{auth: &sfAuth},
+			query: func(sf *Salesforce, sObject *[]account) error {
+				return sf.QueryStructAll(account{}, sObject)
+			},
+			want:    []account{{Id: "123abc", IsDeleted: true}},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*capturedRequest = nil
+			sf := buildSalesforceStruct(tt.fields.auth)
+			got := []account{}
+			if err := tt.query(sf, &got); (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.QueryAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Salesforce.QueryAll() = %v, want %v", got, tt.want)
+			}
+			if !tt.wantErr && !strings.HasSuffix((*capturedRequest).URL.Path, "/queryAll/") {
+				t.Errorf(
+					"Salesforce.QueryAll() path = %v, want /queryAll/",
+					(*capturedRequest).URL.Path,
+				)
+			}
+		})
+	}
+}
+
 func TestSalesforce_InsertOne(t *testing.T) {
 	type account struct {
 		Name string
@@ -1370,6 +1576,54 @@ func TestSalesforce_InsertCollection(t *testing.T) {
 	}
 }
 
+func TestSalesforce_InsertCollectionWithIds(t *testing.T) {
+	type contact struct {
+		Id       string                   `salesforce:"Id,omitempty"`
+		LastName string                   `salesforce:"LastName"`
+		Errors   []SalesforceErrorMessage `salesforce:"-"`
+	}
+	results := []SalesforceResult{
+		{Id: "003A", Success: true},
+		{Errors: []SalesforceErrorMessage{{StatusCode: "DUPLICATE_VALUE", Message: "duplicate"}}},
+	}
+	server, sfAuth := setupTestServer(results, http.StatusOK)
+	defer server.Close()
+
+	t.Run("ids_written_back", func(t *testing.T) {
+		records := []contact{{LastName: "Stark"}, {LastName: "Banner"}}
+		got, err := buildSalesforceStruct(&sfAuth).InsertCollectionWithIds("Contact", &records, 200)
+		if err != nil {
+			t.Fatalf("Salesforce.InsertCollectionWithIds() error = %v", err)
+		}
+		if !got.HasSalesforceErrors {
+			t.Error("Salesforce.InsertCollectionWithIds() HasSalesforceErrors = false")
+		}
+		want := []contact{
+			{Id: "003A", LastName: "Stark"},
+			{LastName: "Banner", Errors: results[1].Errors},
+		}
+		if !reflect.DeepEqual(records, want) {
+			t.Errorf("records = %v, want %v", records, want)
+		}
+	})
+
+	t.Run("not_a_pointer", func(t *testing.T) {
+		records := []contact{{LastName: "Stark"}}
+		_, err := buildSalesforceStruct(&sfAuth).InsertCollectionWithIds("Contact", records, 200)
+		if err == nil {
+			t.Error("Salesforce.InsertCollectionWithIds() expected error")
+		}
+	})
+
+	t.Run("validation_fail", func(t *testing.T) {
+		records := []contact{{LastName: "Stark"}}
+		_, err := buildSalesforceStruct(nil).InsertCollectionWithIds("Contact", &records, 200)
+		if err == nil {
+			t.Error("Salesforce.InsertCollectionWithIds() expected validation error")
+		}
+	})
+}
+
 func TestSalesforce_UpdateCollection(t *testing.T) {
 	type account struct {
 		Id   string
@@ -1793,6 +2047,43 @@ func TestSalesforce_InsertComposite(t *testing.T) {
 	}
 }
 
+func TestSalesforce_InsertCompositeWithIds(t *testing.T) {
+	type contact struct {
+		Id       string `salesforce:"Id,omitempty"`
+		LastName string `salesforce:"LastName"`
+	}
+	compResult := compositeRequestResult{
+		CompositeResponse: []compositeSubRequestResult{
+			{Body: []SalesforceResult{{Id: "003A", Success: true}}, HttpStatusCode: 200},
+			{Body: []SalesforceResult{{Id: "003B", Success: true}}, HttpStatusCode: 200},
+		},
+	}
+	server, sfAuth := setupTestServer(compResult, http.StatusOK)
+	defer server.Close()
+
+	t.Run("ids_written_back", func(t *testing.T) {
+		records := []*contact{{LastName: "Stark"}, {LastName: "Banner"}}
+		_, err := buildSalesforceStruct(&sfAuth).
+			InsertCompositeWithIds("Contact", &records, 1, true)
+		if err != nil {
+			t.Fatalf("Salesforce.InsertCompositeWithIds() error = %v", err)
+		}
+		if records[0].Id != "003A" || records[1].Id != "003B" {
+			t.Errorf("records = %v, %v", records[0], records[1])
+		}
+	})
+
+	t.Run("validation_fail", func(t *testing.T) {
+		records := []contact{{LastName: "Stark"}}
+		_, err := buildSalesforceStruct(
+			&sfAuth,
+		).InsertCompositeWithIds("Contact", &records, 0, true)
+		if err == nil {
+			t.Error("Salesforce.InsertCompositeWithIds() expected validation error")
+		}
+	})
+}
+
 func TestSalesforce_UpdateComposite(t *testing.T) {
 	type account struct {
 		Id   string
@@ -2223,6 +2514,139 @@ func TestSalesforce_InsertBulk(t *testing.T) {
 	}
 }
 
+// setupBulkIngestServer fakes ingest jobs that create each uploaded row with a LastName and fail the others
+func setupBulkIngestServer(t *testing.T) (*httptest.Server, authentication) {
+	var mu sync.Mutex
+	uploads := map[string][][]string{}
+	jobs := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		path := strings.TrimPrefix(r.URL.Path, "/services/data/"+apiVersion+"/jobs/ingest")
+		parts := strings.Split(strings.Trim(path, "/"), "/")
+		switch {
+		case r.Method == http.MethodPost && path == "":
+			jobs++
+			body, _ := json.Marshal(bulkJob{Id: "job" + strconv.Itoa(jobs), State: jobStateOpen})
+			_, _ = w.Write(body)
+		case r.Method == http.MethodPut:
+			rows, err := csv.NewReader(r.Body).ReadAll()
+			if err != nil {
+				t.Error(err.Error())
+			}
+			uploads[parts[0]] = rows
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPatch:
+			w.WriteHeader(http.StatusOK)
+		case len(parts) == 1:
+			job := BulkJobResults{Id: parts[0], State: jobStateJobComplete}
+			if slices.ContainsFunc(uploads[parts[0]], func(row []string) bool {
+				return slices.Contains(row, "Thanos")
+			}) {
+				job.State = jobStateAborted
+			}
+			body, _ := json.Marshal(job)
+			_, _ = w.Write(body)
+		default:
+			rows := uploads[parts[0]]
+			lastName := slices.Index(rows[0], "LastName")
+			writer := csv.NewWriter(w)
+			if parts[1] == unprocessedResults {
+				_ = writer.Write(rows[0])
+				writer.Flush()
+				return
+			}
+			if parts[1] == successfulResults {
+				_ = writer.Write(append([]string{"sf__Id", "sf__Created"}, rows[0]...))
+			} else {
+				_ = writer.Write(append([]string{"sf__Id", "sf__Error"}, rows[0]...))
+			}
+			// results are not in upload order
+			for i := len(rows) - 1; i > 0; i-- {
+				row := rows[i]
+				created := row[lastName] != ""
+				switch {
+				case parts[1] == successfulResults && created:
+					_ = writer.Write(
+						append([]string{parts[0] + "-" + strconv.Itoa(i), "true"}, row...),
+					)
+				case parts[1] == failedResults && !created:
+					missing := "REQUIRED_FIELD_MISSING:Required fields are missing: [LastName]"
+					_ = writer.Write(append([]string{"", missing}, row...))
+				}
+			}
+			writer.Flush()
+		}
+	}))
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+	return server, sfAuth
+}
+
+func TestSalesforce_InsertBulkWithIds(t *testing.T) {
+	type contact struct {
+		Id        string                   `salesforce:"Id"`
+		FirstName string                   `salesforce:"FirstName"`
+		LastName  string                   `salesforce:"LastName"`
+		Errors    []SalesforceErrorMessage `salesforce:"-"`
+	}
+	server, sfAuth := setupBulkIngestServer(t)
+	defer server.Close()
+
+	t.Run("ids_written_back", func(t *testing.T) {
+		records := []contact{
+			{FirstName: "Tony", LastName: "Stark"},
+			{FirstName: "Bruce"},
+			{FirstName: "Tony", LastName: "Stark"},
+		}
+		jobIds, err := buildSalesforceStruct(&sfAuth).InsertBulkWithIds("Contact", &records, 2)
+		if err != nil {
+			t.Fatalf("Salesforce.InsertBulkWithIds() error = %v", err)
+		}
+		if !reflect.DeepEqual(jobIds, []string{"job1", "job2"}) {
+			t.Errorf("Salesforce.InsertBulkWithIds() = %v", jobIds)
+		}
+		if records[0].Id != "job1-1" || records[2].Id != "job2-1" {
+			t.Errorf("Ids = %v, %v, want job1-1, job2-1", records[0].Id, records[2].Id)
+		}
+		if records[1].Id != "" ||
+			!IsErrorCode(&APIError{Errors: records[1].Errors}, "REQUIRED_FIELD_MISSING") {
+			t.Errorf("failed record = %v", records[1])
+		}
+	})
+
+	t.Run("ids_written_back_when_a_job_is_aborted", func(t *testing.T) {
+		records := []contact{
+			{FirstName: "Tony", LastName: "Stark"},
+			{FirstName: "Bruce", LastName: "Banner"},
+			{FirstName: "Thanos"},
+		}
+		_, err := buildSalesforceStruct(&sfAuth).InsertBulkWithIds("Contact", &records, 2)
+		if err == nil || !strings.Contains(err.Error(), "bulk job aborted") {
+			t.Errorf("Salesforce.InsertBulkWithIds() error = %v, want aborted error", err)
+		}
+		if records[0].Id == "" || records[1].Id == "" || records[2].Id != "" {
+			t.Errorf("Ids = %q, %q, %q, want Ids for the completed job only",
+				records[0].Id, records[1].Id, records[2].Id)
+		}
+	})
+
+	t.Run("not_a_pointer", func(t *testing.T) {
+		records := []contact{{LastName: "Stark"}}
+		_, err := buildSalesforceStruct(&sfAuth).InsertBulkWithIds("Contact", records, 2)
+		if err == nil {
+			t.Error("Salesforce.InsertBulkWithIds() expected error")
+		}
+	})
+
+	t.Run("validation_fail", func(t *testing.T) {
+		records := []contact{{LastName: "Stark"}}
+		_, err := buildSalesforceStruct(nil).InsertBulkWithIds("Contact", &records, 2)
+		if err == nil {
+			t.Error("Salesforce.InsertBulkWithIds() expected validation error")
+		}
+	})
+}
+
 func TestSalesforce_InsertBulkAssign(t *testing.T) {
 	type account struct {
 		Name string
@@ -2826,6 +3250,95 @@ func TestSalesforce_GetJobResults(t *testing.T) {
 	}
 }
 
+func TestSalesforce_WaitForBulkJobs(t *testing.T) {
+	server, sfAuth := setupBulkJobsServer(t)
+	defer server.Close()
+
+	t.Run("results_in_order", func(t *testing.T) {
+		results, err := buildSalesforceStruct(&sfAuth).WaitForBulkJobs([]string{"job3", "job1"})
+		if err != nil {
+			t.Fatalf("Salesforce.WaitForBulkJobs() error = %v", err)
+		}
+		if len(results) != 2 || results[0].Id != "job3" || results[1].Id != "job1" {
+			t.Fatalf("Salesforce.WaitForBulkJobs() = %v", results)
+		}
+		if len(results[0].Successful) != 1 || results[0].Successful[0].Id != "job3-1" {
+			t.Errorf("Salesforce.WaitForBulkJobs() successful records = %v", results[0].Successful)
+		}
+	})
+
+	t.Run("failed_job", func(t *testing.T) {
+		results, err := buildSalesforceStruct(&sfAuth).WaitForBulkJobs([]string{"job1", "job2"})
+		if err == nil || !strings.Contains(err.Error(), "job2") {
+			t.Errorf("Salesforce.WaitForBulkJobs() error = %v, want error for job2", err)
+		}
+		if len(results) != 2 || results[1].State != jobStateFailed ||
+			len(results[1].Failed) != 1 {
+			t.Errorf("Salesforce.WaitForBulkJobs() = %v", results)
+		}
+	})
+
+	t.Run("aborted_job", func(t *testing.T) {
+		results, err := buildSalesforceStruct(&sfAuth).WaitForBulkJobs([]string{"job5"})
+		if err == nil || !strings.Contains(err.Error(), "bulk job aborted") {
+			t.Errorf("Salesforce.WaitForBulkJobs() error = %v, want aborted error", err)
+		}
+		if len(results) != 1 || results[0].State != jobStateAborted ||
+			len(results[0].Successful) != 1 || len(results[0].Unprocessed) != 1 ||
+			results[0].Unprocessed[0].Fields["Name"] != "unprocessed" {
+			t.Errorf("Salesforce.WaitForBulkJobs() = %v", results)
+		}
+	})
+
+	t.Run("validation_fail", func(t *testing.T) {
+		if _, err := buildSalesforceStruct(nil).WaitForBulkJobs([]string{"job1"}); err == nil {
+			t.Error("Salesforce.WaitForBulkJobs() expected validation error")
+		}
+	})
+}
+
+func TestSalesforce_AttachBulkIngest(t *testing.T) {
+	server, sfAuth := setupBulkJobsServer(t)
+	defer server.Close()
+
+	t.Run("results_in_order", func(t *testing.T) {
+		results, err := buildSalesforceStruct(&sfAuth).AttachBulkIngest([]string{"job3", "job1"})
+		if err != nil {
+			t.Fatalf("Salesforce.AttachBulkIngest() error = %v", err)
+		}
+		if len(results) != 2 || results[0].Id != "job3" || results[1].Id != "job1" {
+			t.Fatalf("Salesforce.AttachBulkIngest() = %v", results)
+		}
+		if len(results[1].Successful) != 1 || results[1].Successful[0].Id != "job1-1" {
+			t.Errorf("Salesforce.AttachBulkIngest() successful records = %v", results[1].Successful)
+		}
+	})
+
+	t.Run("open_and_failed_jobs", func(t *testing.T) {
+		results, err := buildSalesforceStruct(&sfAuth).AttachBulkIngest(
+			[]string{"job4", "job2", "job1", ""},
+		)
+		if err == nil || !strings.Contains(err.Error(), "bulk job job4: job is still open") ||
+			!strings.Contains(err.Error(), "bulk job job2") ||
+			!strings.Contains(err.Error(), "bulk job id is required") {
+			t.Errorf(
+				"Salesforce.AttachBulkIngest() error = %v, want errors for job4, job2 and the empty id",
+				err,
+			)
+		}
+		if len(results) != 4 || results[0].State != jobStateOpen ||
+			results[1].State != jobStateFailed || len(results[2].Successful) != 1 {
+			t.Errorf("Salesforce.AttachBulkIngest() = %v", results)
+		}
+	})
+
+	t.Run("validation_fail", func(t *testing.T) {
+		if _, err := buildSalesforceStruct(nil).AttachBulkIngest([]string{"job1"}); err == nil {
+			t.Error("Salesforce.AttachBulkIngest() expected validation error")
+		}
+	})
+}
+
 func TestSalesforce_InsertBulkFile(t *testing.T) {
 	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
 	if err := appFs.MkdirAll("data", 0o755); err != nil {
@@ -3425,6 +3938,334 @@ func TestSalesforce_DeleteBulkFile(t *testing.T) {
 	}
 }
 
+func TestSalesforce_InsertBulkReader(t *testing.T) {
+	job := bulkJob{
+		Id:    "1234",
+		State: jobStateOpen,
+	}
+	server, sfAuth := setupTestServer(job, http.StatusOK)
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		data      string
+		batchSize int
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "insert bulk data successfully",
+			data:      "Name\nrow1\nrow2\nrow3\n",
+			batchSize: 2,
+			want:      []string{"1234", "1234"},
+			wantErr:   false,
+		},
+		{
+			name:      "no header row",
+			data:      "",
+			batchSize: 2000,
+			want:      nil,
+			wantErr:   true,
+		},
+		{
+			name:      "validation error",
+			data:      "Name\nrow\n",
+			batchSize: 10001,
+			want:      []string{},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := buildSalesforceStruct(&sfAuth)
+			got, err := sf.InsertBulkReader(
+				"Account",
+				strings.NewReader(tt.data),
+				tt.batchSize,
+				false,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.InsertBulkReader() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Salesforce.InsertBulkReader() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_UpdateBulkReader(t *testing.T) {
+	job := bulkJob{
+		Id:    "1234",
+		State: jobStateOpen,
+	}
+	server, sfAuth := setupTestServer(job, http.StatusOK)
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		data      string
+		batchSize int
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "update bulk data successfully",
+			data:      "Id\nrow1\nrow2\nrow3\n",
+			batchSize: 2,
+			want:      []string{"1234", "1234"},
+			wantErr:   false,
+		},
+		{
+			name:      "no header row",
+			data:      "",
+			batchSize: 2000,
+			want:      nil,
+			wantErr:   true,
+		},
+		{
+			name:      "validation error",
+			data:      "Id\nrow\n",
+			batchSize: 10001,
+			want:      []string{},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := buildSalesforceStruct(&sfAuth)
+			got, err := sf.UpdateBulkReader(
+				"Account",
+				strings.NewReader(tt.data),
+				tt.batchSize,
+				false,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.UpdateBulkReader() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Salesforce.UpdateBulkReader() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_UpsertBulkReader(t *testing.T) {
+	job := bulkJob{
+		Id:    "1234",
+		State: jobStateOpen,
+	}
+	server, sfAuth := setupTestServer(job, http.StatusOK)
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		data      string
+		batchSize int
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "upsert bulk data successfully",
+			data:      "ExternalId__c\nrow1\nrow2\nrow3\n",
+			batchSize: 2,
+			want:      []string{"1234", "1234"},
+			wantErr:   false,
+		},
+		{
+			name:      "no header row",
+			data:      "",
+			batchSize: 2000,
+			want:      nil,
+			wantErr:   true,
+		},
+		{
+			name:      "validation error",
+			data:      "ExternalId__c\nrow\n",
+			batchSize: 10001,
+			want:      []string{},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := buildSalesforceStruct(&sfAuth)
+			got, err := sf.UpsertBulkReader(
+				"Account",
+				"ExternalId__c",
+				strings.NewReader(tt.data),
+				tt.batchSize,
+				false,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.UpsertBulkReader() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Salesforce.UpsertBulkReader() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_HardDeleteBulk(t *testing.T) {
+	var mu sync.Mutex
+	var jobRequests []bulkJobCreationRequest
+	var uploads []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPost:
+			request := bulkJobCreationRequest{}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Fatal(err.Error())
+			}
+			jobRequests = append(jobRequests, request)
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			uploads = append(uploads, string(data))
+		}
+		body, _ := json.Marshal(bulkJob{Id: "1234", State: jobStateOpen})
+		if _, err := w.Write(body); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+	sf := buildSalesforceStruct(&sfAuth)
+
+	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+	if err := afero.WriteFile(appFs, "data/delete.csv", []byte("Id|Name\n001|a,b\n"), 0o644); err != nil {
+		t.Fatalf("error creating file in virtual file system")
+	}
+
+	t.Run("hard_delete_records", func(t *testing.T) {
+		jobRequests, uploads = nil, nil
+		records := []map[string]any{{"Id": "001"}}
+		got, err := sf.HardDeleteBulk("Account", records, 2000, false)
+		if err != nil {
+			t.Fatalf("Salesforce.HardDeleteBulk() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, []string{"1234"}) {
+			t.Errorf("Salesforce.HardDeleteBulk() = %v", got)
+		}
+		want := bulkJobCreationRequest{Object: "Account", Operation: hardDeleteOperation}
+		if len(jobRequests) != 1 || jobRequests[0] != want {
+			t.Errorf("Salesforce.HardDeleteBulk() job requests = %+v, want %+v", jobRequests, want)
+		}
+	})
+
+	t.Run("hard_delete_pipe_delimited_file", func(t *testing.T) {
+		jobRequests, uploads = nil, nil
+		_, err := sf.HardDeleteBulkFile(
+			"Account",
+			"data/delete.csv",
+			2000,
+			false,
+			WithColumnDelimiter(ColumnDelimiterPipe),
+			WithLineEnding(LineEndingCRLF),
+		)
+		if err != nil {
+			t.Fatalf("Salesforce.HardDeleteBulkFile() error = %v", err)
+		}
+		want := bulkJobCreationRequest{
+			Object:          "Account",
+			Operation:       hardDeleteOperation,
+			ColumnDelimiter: ColumnDelimiterPipe,
+			LineEnding:      LineEndingCRLF,
+		}
+		if len(jobRequests) != 1 || jobRequests[0] != want {
+			t.Errorf(
+				"Salesforce.HardDeleteBulkFile() job requests = %+v, want %+v",
+				jobRequests,
+				want,
+			)
+		}
+		if !reflect.DeepEqual(uploads, []string{"Id|Name\r\n001|a,b\r\n"}) {
+			t.Errorf("Salesforce.HardDeleteBulkFile() uploaded %q", uploads)
+		}
+	})
+
+	t.Run("invalid_options", func(t *testing.T) {
+		for _, opt := range []BulkJobOption{WithColumnDelimiter("COLON"), WithMaxRecords(100)} {
+			jobRequests = nil
+			_, err := sf.HardDeleteBulkFile("Account", "data/delete.csv", 2000, false, opt)
+			if err == nil || len(jobRequests) > 0 {
+				t.Errorf(
+					"Salesforce.HardDeleteBulkFile() error = %v, want error before the job",
+					err,
+				)
+			}
+		}
+	})
+
+	t.Run("validation_fail", func(t *testing.T) {
+		if _, err := buildSalesforceStruct(nil).HardDeleteBulk("Account", []map[string]any{}, 2000, false); err == nil {
+			t.Error("Salesforce.HardDeleteBulk() expected validation error")
+		}
+	})
+}
+
+func TestSalesforce_DeleteBulkReader(t *testing.T) {
+	job := bulkJob{
+		Id:    "1234",
+		State: jobStateOpen,
+	}
+	server, sfAuth := setupTestServer(job, http.StatusOK)
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		data      string
+		batchSize int
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "delete bulk data successfully",
+			data:      "Id\nrow1\nrow2\nrow3\n",
+			batchSize: 2,
+			want:      []string{"1234", "1234"},
+			wantErr:   false,
+		},
+		{
+			name:      "no header row",
+			data:      "",
+			batchSize: 2000,
+			want:      nil,
+			wantErr:   true,
+		},
+		{
+			name:      "validation error",
+			data:      "Id\nrow\n",
+			batchSize: 10001,
+			want:      []string{},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := buildSalesforceStruct(&sfAuth)
+			got, err := sf.DeleteBulkReader(
+				"Account",
+				strings.NewReader(tt.data),
+				tt.batchSize,
+				false,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.DeleteBulkReader() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Salesforce.DeleteBulkReader() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSalesforce_QueryBulkExport(t *testing.T) {
 	job := bulkJob{
 		Id:    "1234",
@@ -3512,6 +4353,93 @@ func TestSalesforce_QueryBulkExport(t *testing.T) {
 	}
 }
 
+func TestSalesforce_QueryBulkExportTo(t *testing.T) {
+	jobCreationRespBody, _ := json.Marshal(bulkJob{Id: "1234", State: jobStateJobComplete})
+	jobResultsRespBody, _ := json.Marshal(
+		BulkJobResults{Id: "1234", State: jobStateJobComplete},
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			if _, err := w.Write(jobCreationRespBody); err != nil {
+				t.Fatal(err.Error())
+			}
+		case strings.HasSuffix(r.URL.Path, "/1234"):
+			if _, err := w.Write(jobResultsRespBody); err != nil {
+				t.Fatal(err.Error())
+			}
+		default:
+			locator := "next"
+			if r.URL.Query().Get("locator") == "next" {
+				locator = "null"
+			}
+			w.Header().Add("Sforce-Locator", locator)
+			if _, err := w.Write([]byte("\"Id\"\n\"" + locator + "\"\n")); err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+
+	badServer, badAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	t.Run("export_pages_to_writer", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := buildSalesforceStruct(&sfAuth).QueryBulkExportTo("SELECT Id FROM Account", &buf)
+		if err != nil {
+			t.Fatalf("Salesforce.QueryBulkExportTo() error = %v", err)
+		}
+		if want := "Id\nnext\nnull\n"; buf.String() != want {
+			t.Errorf("Salesforce.QueryBulkExportTo() wrote %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("export_with_concurrent_downloads", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := buildSalesforceStruct(&sfAuth).QueryBulkExportTo(
+			"SELECT Id FROM Account",
+			&buf,
+			WithMaxRecords(50000),
+			WithConcurrentDownloads(4),
+		)
+		if err != nil {
+			t.Fatalf("Salesforce.QueryBulkExportTo() error = %v", err)
+		}
+		if want := "Id\nnext\nnull\n"; buf.String() != want {
+			t.Errorf("Salesforce.QueryBulkExportTo() wrote %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("invalid_option", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := buildSalesforceStruct(&sfAuth).QueryBulkExportTo(
+			"SELECT Id FROM Account",
+			&buf,
+			WithConcurrentDownloads(0),
+		)
+		if err == nil || buf.Len() > 0 {
+			t.Errorf("Salesforce.QueryBulkExportTo() error = %v, want error before the export", err)
+		}
+	})
+
+	t.Run("nil_writer", func(t *testing.T) {
+		err := buildSalesforceStruct(&sfAuth).QueryBulkExportTo("SELECT Id FROM Account", nil)
+		if err == nil {
+			t.Error("Salesforce.QueryBulkExportTo() expected error")
+		}
+	})
+
+	t.Run("request_error", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := buildSalesforceStruct(&badAuth).QueryBulkExportTo("SELECT Id FROM Account", &buf)
+		if err == nil {
+			t.Error("Salesforce.QueryBulkExportTo() expected error")
+		}
+	})
+}
+
 func TestSalesforce_QueryStructBulkExport(t *testing.T) {
 	type account struct {
 		Id   string
@@ -3794,3 +4722,180 @@ func TestGetInstanceUrl(t *testing.T) {
 		})
 	}
 }
+
+func TestSalesforce_ResumeQueryIterator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/results") {
+			body, _ := json.Marshal(BulkJobResults{Id: "750", State: jobStateJobComplete})
+			if _, err := w.Write(body); err != nil {
+				t.Fatal(err.Error())
+			}
+			return
+		}
+		next := map[string]string{"": "page2", "page2": "page3", "page3": "null"}
+		locator := r.URL.Query().Get("locator")
+		w.Header().Add("Sforce-Locator", next[locator])
+		if _, err := w.Write([]byte("\"Id\"\n\"" + locator + "\"\n")); err != nil {
+			t.Fatal(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+	sf := buildSalesforceStruct(&sfAuth)
+
+	t.Run("resume_from_locator", func(t *testing.T) {
+		it, err := sf.ResumeQueryIterator("750", "page2")
+		if err != nil {
+			t.Fatalf("Salesforce.ResumeQueryIterator() error = %v", err)
+		}
+		var rows, locators []string
+		for it.Next() {
+			var page []struct{ Id string }
+			if err := it.Decode(&page); err != nil {
+				t.Fatalf("ResumableIterator.Decode() error = %v", err)
+			}
+			rows = append(rows, page[0].Id)
+			locators = append(locators, it.Locator())
+			if it.JobId() != "750" {
+				t.Errorf("ResumableIterator.JobId() = %v, want 750", it.JobId())
+			}
+		}
+		if err := it.Error(); err != nil {
+			t.Fatalf("ResumableIterator.Error() = %v", err)
+		}
+		if !reflect.DeepEqual(rows, []string{"page2", "page3"}) ||
+			!reflect.DeepEqual(locators, []string{"page3", "null"}) {
+			t.Errorf("ResumableIterator rows = %v, locators = %v", rows, locators)
+		}
+	})
+
+	t.Run("resume_after_last_page", func(t *testing.T) {
+		it, err := sf.ResumeQueryIterator("750", "null")
+		if err != nil {
+			t.Fatalf("Salesforce.ResumeQueryIterator() error = %v", err)
+		}
+		if it.Next() {
+			t.Error("ResumableIterator.Next() = true, want no more pages")
+		}
+	})
+
+	t.Run("pk_chunking", func(t *testing.T) {
+		if _, err := sf.ResumeQueryIterator("750", "", WithPKChunking(1, 2)); err == nil {
+			t.Error("Salesforce.ResumeQueryIterator() expected error")
+		}
+	})
+
+	t.Run("missing_job_id", func(t *testing.T) {
+		if _, err := sf.ResumeQueryIterator("", ""); err == nil {
+			t.Error("Salesforce.ResumeQueryIterator() expected error")
+		}
+	})
+
+	t.Run("validation_fail", func(t *testing.T) {
+		if _, err := buildSalesforceStruct(nil).ResumeQueryIterator("750", ""); err == nil {
+			t.Error("Salesforce.ResumeQueryIterator() expected validation error")
+		}
+	})
+}
+
+func TestSalesforce_ListBulkJobs(t *testing.T) {
+	list := bulkJobList{Done: true, Records: []BulkJobInfo{{Id: "750", State: jobStateOpen}}}
+	server, sfAuth := setupTestServer(list, http.StatusOK)
+	defer server.Close()
+
+	t.Run("list_jobs", func(t *testing.T) {
+		got, err := buildSalesforceStruct(&sfAuth).ListBulkJobs(BulkJobFilter{})
+		if err != nil {
+			t.Fatalf("Salesforce.ListBulkJobs() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, list.Records) {
+			t.Errorf("Salesforce.ListBulkJobs() = %v, want %v", got, list.Records)
+		}
+	})
+
+	t.Run("validation_fail", func(t *testing.T) {
+		if _, err := buildSalesforceStruct(nil).ListBulkJobs(BulkJobFilter{}); err == nil {
+			t.Error("Salesforce.ListBulkJobs() expected validation error")
+		}
+	})
+}
+
+func TestSalesforce_GetBulkJob(t *testing.T) {
+	job := BulkJobInfo{
+		Id:                     "750",
+		Operation:              queryJobType,
+		State:                  jobStateJobComplete,
+		ConcurrencyMode:        "Parallel",
+		ApiVersion:             63.0,
+		NumberRecordsProcessed: 10,
+		TotalProcessingTime:    250,
+	}
+	server, sfAuth, req := setupTestServerWithCapture(job, http.StatusOK)
+	defer server.Close()
+
+	t.Run("get_query_job", func(t *testing.T) {
+		got, err := buildSalesforceStruct(&sfAuth).GetBulkJob("750", BulkQueryJob)
+		if err != nil {
+			t.Fatalf("Salesforce.GetBulkJob() error = %v", err)
+		}
+		if got != job {
+			t.Errorf("Salesforce.GetBulkJob() = %v, want %v", got, job)
+		}
+		if wantPath := "/services/data/" + apiVersion + "/jobs/query/750"; (*req).URL.Path != wantPath {
+			t.Errorf("request path = %v, want %v", (*req).URL.Path, wantPath)
+		}
+	})
+
+	t.Run("validation_fail", func(t *testing.T) {
+		if _, err := buildSalesforceStruct(nil).GetBulkJob("750", BulkQueryJob); err == nil {
+			t.Error("Salesforce.GetBulkJob() expected validation error")
+		}
+	})
+}
+
+func TestSalesforce_AbortBulkJob(t *testing.T) {
+	server, sfAuth, req := setupTestServerWithCapture(
+		BulkJobInfo{Id: "750", State: jobStateAborted},
+		http.StatusOK,
+	)
+	defer server.Close()
+
+	t.Run("abort_ingest_job", func(t *testing.T) {
+		got, err := buildSalesforceStruct(&sfAuth).AbortBulkJob("750", BulkIngestJob)
+		if err != nil {
+			t.Fatalf("Salesforce.AbortBulkJob() error = %v", err)
+		}
+		if got.State != jobStateAborted || (*req).Method != http.MethodPatch {
+			t.Errorf("Salesforce.AbortBulkJob() = %v with %s request", got, (*req).Method)
+		}
+	})
+
+	t.Run("invalid_job_type", func(t *testing.T) {
+		if _, err := buildSalesforceStruct(&sfAuth).AbortBulkJob("750", "batch"); err == nil {
+			t.Error("Salesforce.AbortBulkJob() expected error")
+		}
+	})
+}
+
+func TestSalesforce_DeleteBulkJob(t *testing.T) {
+	server, sfAuth, req := setupTestServerWithCapture("", http.StatusNoContent)
+	defer server.Close()
+
+	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	t.Run("delete_ingest_job", func(t *testing.T) {
+		if err := buildSalesforceStruct(&sfAuth).DeleteBulkJob("750", BulkIngestJob); err != nil {
+			t.Fatalf("Salesforce.DeleteBulkJob() error = %v", err)
+		}
+		if (*req).Method != http.MethodDelete {
+			t.Errorf("Salesforce.DeleteBulkJob() sent %s request", (*req).Method)
+		}
+	})
+
+	t.Run("job_not_finished", func(t *testing.T) {
+		if err := buildSalesforceStruct(&badSfAuth).DeleteBulkJob("750", BulkIngestJob); err == nil {
+			t.Error("Salesforce.DeleteBulkJob() expected error")
+		}
+	})
+}
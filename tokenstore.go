@@ -0,0 +1,119 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// Token is the part of a session that is persisted by a TokenStore.
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	InstanceUrl  string `json:"instance_url"`
+	IssuedAt     string `json:"issued_at,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// TokenStore persists the session token so it can be reused across restarts and shared between processes.
+//
+// Init loads the stored token and only logs in when there is none. Whenever a new token is obtained,
+// by Init or by refreshing an expired session, it is saved to the store. Before refreshing, the store
+// is checked for a token saved by another process, so the org's login limits are not spent on every instance.
+// A store should only be shared by clients that authenticate as the same user into the same org.
+type TokenStore interface {
+	// Load returns the stored token, or nil if there is none.
+	Load(ctx context.Context) (*Token, error)
+	// Save replaces the stored token.
+	Save(ctx context.Context, token Token) error
+}
+
+// MemoryTokenStore keeps the token in memory. It is safe for concurrent use and can be shared
+// by multiple Salesforce instances in the same process.
+type MemoryTokenStore struct {
+	mu    sync.RWMutex
+	token *Token
+}
+
+// NewMemoryTokenStore returns an empty MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{}
+}
+
+func (s *MemoryTokenStore) Load(ctx context.Context) (*Token, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.token == nil {
+		return nil, nil
+	}
+	token := *s.token
+	return &token, nil
+}
+
+func (s *MemoryTokenStore) Save(ctx context.Context, token Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = &token
+	return nil
+}
+
+// FileTokenStore keeps the token in a JSON file that is readable only by the current user.
+// The file is replaced atomically, so processes sharing it never read a partially written token.
+type FileTokenStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileTokenStore returns a FileTokenStore backed by the file at path.
+// The file is created on the first save; its directory must already exist.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+func (s *FileTokenStore) Load(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := afero.ReadFile(appFs, s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	token := &Token{}
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (s *FileTokenStore) Save(ctx context.Context, token Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := afero.TempFile(appFs, filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if err := appFs.Chmod(file.Name(), 0o600); err != nil {
+		_ = file.Close()
+		_ = appFs.Remove(file.Name())
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		_ = appFs.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		_ = appFs.Remove(file.Name())
+		return err
+	}
+	return appFs.Rename(file.Name(), s.path)
+}
@@ -0,0 +1,285 @@
+package salesforce
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestMemoryTokenStore(t *testing.T) {
+	store := NewMemoryTokenStore()
+	got, err := store.Load(context.Background())
+	if err != nil || got != nil {
+		t.Fatalf("Load() on empty store = %v, %v, want nil, nil", got, err)
+	}
+
+	want := Token{AccessToken: "1234", InstanceUrl: "example.com", RefreshToken: "refresh"}
+	if err := store.Save(context.Background(), want); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, err = store.Load(context.Background())
+	if err != nil || got == nil || *got != want {
+		t.Errorf("Load() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestFileTokenStore(t *testing.T) {
+	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+	if err := appFs.MkdirAll("data", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := afero.WriteFile(appFs, "data/corrupt.json", []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	token := Token{AccessToken: "1234", InstanceUrl: "example.com", IssuedAt: "01/01/1970"}
+
+	tests := []struct {
+		name    string
+		path    string
+		save    *Token
+		want    *Token
+		wantErr bool
+	}{
+		{
+			name:    "missing_file",
+			path:    "data/missing.json",
+			save:    nil,
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name:    "save_and_load",
+			path:    "data/token.json",
+			save:    &token,
+			want:    &token,
+			wantErr: false,
+		},
+		{
+			name:    "corrupt_file",
+			path:    "data/corrupt.json",
+			save:    nil,
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewFileTokenStore(tt.path)
+			if tt.save != nil {
+				if err := store.Save(context.Background(), *tt.save); err != nil {
+					t.Fatalf("Save() error = %v", err)
+				}
+				info, err := appFs.Stat(tt.path)
+				if err != nil {
+					t.Fatalf("Save() did not create file: %v", err)
+				}
+				if perm := info.Mode().Perm(); perm != 0o600 {
+					t.Errorf("Save() file mode = %v, want 0600", perm)
+				}
+			}
+			got, err := store.Load(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	entries, _ := afero.ReadDir(appFs, "data")
+	if len(entries) != 2 {
+		t.Errorf("Save() left temporary files behind: %v", entries)
+	}
+}
+
+func setupCountingAuthServer(token authentication) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	server, _ := setupTestServer(token, http.StatusOK)
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		handler.ServeHTTP(w, r)
+	})
+	return server, &calls
+}
+
+func TestInit_tokenStore(t *testing.T) {
+	server, calls := setupCountingAuthServer(authentication{
+		AccessToken: "fresh",
+		InstanceUrl: "example.com",
+	})
+	defer server.Close()
+	creds := Creds{Domain: server.URL, ConsumerKey: "key", ConsumerSecret: "secret"}
+
+	tests := []struct {
+		name      string
+		stored    *Token
+		wantToken Token
+		wantCalls int32
+	}{
+		{
+			name:      "login_and_save",
+			stored:    nil,
+			wantToken: Token{AccessToken: "fresh", InstanceUrl: "example.com"},
+			wantCalls: 1,
+		},
+		{
+			name:      "reuse_stored_token",
+			stored:    &Token{AccessToken: "stored", InstanceUrl: "example.com"},
+			wantToken: Token{AccessToken: "stored", InstanceUrl: "example.com"},
+			wantCalls: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls.Store(0)
+			store := NewMemoryTokenStore()
+			if tt.stored != nil {
+				_ = store.Save(context.Background(), *tt.stored)
+			}
+			sf, err := Init(creds, WithTokenStore(store))
+			if err != nil {
+				t.Fatalf("Init() error = %v", err)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("Init() login calls = %v, want %v", got, tt.wantCalls)
+			}
+			if sf.GetAccessToken() != tt.wantToken.AccessToken {
+				t.Errorf(
+					"Init() access token = %v, want %v",
+					sf.GetAccessToken(),
+					tt.wantToken.AccessToken,
+				)
+			}
+			if sf.GetAuthFlow() != AuthFlowClientCredentials {
+				t.Errorf(
+					"Init() auth flow = %v, want %v",
+					sf.GetAuthFlow(),
+					AuthFlowClientCredentials,
+				)
+			}
+			saved, _ := store.Load(context.Background())
+			if saved == nil || *saved != tt.wantToken {
+				t.Errorf("stored token = %v, want %v", saved, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestInit_tokenStoreWithAccessToken(t *testing.T) {
+	server, _ := setupTestServer(authentication{}, http.StatusOK)
+	defer server.Close()
+	store := NewMemoryTokenStore()
+	_ = store.Save(context.Background(), Token{AccessToken: "stale", InstanceUrl: server.URL})
+
+	sf, err := Init(Creds{Domain: server.URL, AccessToken: "fresh"}, WithTokenStore(store))
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if sf.GetAccessToken() != "fresh" {
+		t.Errorf("Init() access token = %v, want fresh", sf.GetAccessToken())
+	}
+	saved, _ := store.Load(context.Background())
+	if saved == nil || saved.AccessToken != "fresh" {
+		t.Errorf("stored token = %v, want the access token passed to Init", saved)
+	}
+}
+
+func TestInit_tokenStoreCachesDescribes(t *testing.T) {
+	var conditionalCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") != "" {
+			conditionalCalls.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+		if _, err := w.Write([]byte(`{"name":"Account"}`)); err != nil {
+			panic(err)
+		}
+	}))
+	defer server.Close()
+
+	store := NewMemoryTokenStore()
+	_ = store.Save(context.Background(), Token{AccessToken: "stored", InstanceUrl: server.URL})
+	sf, err := Init(
+		Creds{Domain: server.URL, ConsumerKey: "key", ConsumerSecret: "secret"},
+		WithTokenStore(store),
+	)
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	for range 2 {
+		if _, err := sf.DescribeSObject("Account"); err != nil {
+			t.Fatalf("Salesforce.DescribeSObject() error = %v", err)
+		}
+	}
+	if got := conditionalCalls.Load(); got != 1 {
+		t.Errorf("conditional describe requests = %v, want 1 served from the cache", got)
+	}
+}
+
+func Test_refreshSession_tokenStore(t *testing.T) {
+	server, calls := setupCountingAuthServer(authentication{AccessToken: "refreshed"})
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		stored    Token
+		wantToken string
+		wantCalls int32
+	}{
+		{
+			name:      "adopt_token_refreshed_elsewhere",
+			stored:    Token{AccessToken: "other_process", InstanceUrl: server.URL},
+			wantToken: "other_process",
+			wantCalls: 0,
+		},
+		{
+			name:      "refresh_and_save",
+			stored:    Token{AccessToken: "accesstokenvalue", InstanceUrl: server.URL},
+			wantToken: "refreshed",
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls.Store(0)
+			store := NewMemoryTokenStore()
+			_ = store.Save(context.Background(), tt.stored)
+			sfAuth := authentication{
+				InstanceUrl: server.URL,
+				AccessToken: "accesstokenvalue",
+				grantType:   grantTypeClientCredentials,
+				session:     &session{},
+			}
+			config := getDefaultConfig(t)
+			config.tokenStore = store
+
+			if err := refreshSession(context.Background(), &sfAuth, config); err != nil {
+				t.Fatalf("refreshSession() error = %v", err)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("refreshSession() login calls = %v, want %v", got, tt.wantCalls)
+			}
+			if sfAuth.AccessToken != tt.wantToken {
+				t.Errorf(
+					"refreshSession() access token = %v, want %v",
+					sfAuth.AccessToken,
+					tt.wantToken,
+				)
+			}
+			saved, _ := store.Load(context.Background())
+			if saved.AccessToken != tt.wantToken {
+				t.Errorf("stored access token = %v, want %v", saved.AccessToken, tt.wantToken)
+			}
+		})
+	}
+}